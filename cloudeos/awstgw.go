@@ -1,10 +1,11 @@
 package cloudeos
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"time"
+	"strconv"
 
 	api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
 
@@ -15,13 +16,78 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
+func (p *CloudeosProvider) GetAwsVpnConfigResponse(d *schema.ResourceData) (
+	*api.AWSVpnConfigResponse, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("GetAwsVpnConfig: Failed to create new CVaaS Grpc client, err: %v", err)
+		return nil, err
+	}
+
+	awsVpnClient := api.NewAWSVpnConfigServiceClient(client)
+	awsVpnKey := &api.AWSVpnKey{
+		TfId: &wrappers.StringValue{Value: d.Get("tf_id").(string)},
+	}
+	awsVpnConfigRequest := api.AWSVpnConfigRequest{
+		Key: awsVpnKey,
+	}
+
+	log.Printf("[CVaaS-INFO] GetAwsVpnConfigRequest: %v", redact(&awsVpnConfigRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	resp, err := awsVpnClient.GetOne(ctx, &awsVpnConfigRequest)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[CVaaS-INFO] Received GetAwsVpnConfigResponse: %v", redact(resp))
+	return resp, nil
+}
+
+func (p *CloudeosProvider) GetAwsVpnConfigByConnectionID(vpnConnectionID string) (
+	*api.AWSVpnConfig, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("GetAwsVpnConfigByConnectionID: Failed to create new CVaaS Grpc client, err: %v", err)
+		return nil, err
+	}
+
+	awsVpnClient := api.NewAWSVpnConfigServiceClient(client)
+	awsVpnConfig := &api.AWSVpnConfig{
+		VpnConnectionId: &wrappers.StringValue{Value: vpnConnectionID},
+	}
+	awsVpnConfigStreamRequest := api.AWSVpnConfigStreamRequest{
+		PartialEqFilter: []*api.AWSVpnConfig{awsVpnConfig},
+	}
+
+	log.Printf("[CVaaS-INFO] GetAllAwsVpnConfigRequest: %v", redact(&awsVpnConfigStreamRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	stream, err := awsVpnClient.GetAll(ctx, &awsVpnConfigStreamRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading grpc stream: %v", err)
+		}
+		if resp.GetValue().GetVpnConnectionId().GetValue() == vpnConnectionID {
+			return resp.GetValue(), nil
+		}
+	}
+	return nil, fmt.Errorf("No aws vpn entry found for vpn_connection_id %s", vpnConnectionID)
+}
+
 func (p *CloudeosProvider) DeleteAwsVpnConfig(d *schema.ResourceData) error {
 	client, err := p.grpcClient()
 	if err != nil {
 		log.Printf("DeleteAwsVpnConfig: Failed to create new CVaaS Grpc client, err: %v", err)
 		return err
 	}
-	defer client.Close()
 
 	awsVpnClient := api.NewAWSVpnConfigServiceClient(client)
 	awsVpnKey := &api.AWSVpnKey{
@@ -31,58 +97,61 @@ func (p *CloudeosProvider) DeleteAwsVpnConfig(d *schema.ResourceData) error {
 		Key: awsVpnKey,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] DeleteAwsVpnConfigRequest: %v", redact(&awsVpnConfigDeleteRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := awsVpnClient.Delete(ctx, &awsVpnConfigDeleteRequest)
-	if err != nil && resp != nil && resp.Key.GetTfId().GetValue() != d.Get("tf_id").(string) {
+	if err != nil {
+		log.Printf("DeleteAwsVpnConfig failed, error: %v", err)
+		return err
+	}
+	log.Printf("[CVaaS-INFO] DeleteAwsVpnConfigResponse: %v", redact(resp))
+	if resp.GetKey().GetTfId().GetValue() != d.Get("tf_id").(string) {
 		return fmt.Errorf("Deleted key %v, tf_id %v", resp.GetKey().GetTfId().GetValue(),
 			d.Get("tf_id").(string))
 	}
 	return nil
+}
 
+// CheckAwsVpnDeletionStatus returns an error while the aws vpn entry still
+// exists in CVaaS
+func (p *CloudeosProvider) CheckAwsVpnDeletionStatus(d *schema.ResourceData) error {
+	resp, err := p.GetAwsVpnConfigResponse(d)
+	if err != nil {
+		return err
+	}
+	// An empty entry is returned when there's no entry for the key
+	if resp.GetValue().GetKey().GetTfId().GetValue() != "" {
+		return errors.New("Aws vpn resource exists")
+	}
+	return nil
 }
 
-func (p *CloudeosProvider) AddAwsVpnConfig(d *schema.ResourceData) error {
+// AddAwsVpnConfig sets the VPN connection in CVaaS
+func (p *CloudeosProvider) AddAwsVpnConfig(d *schema.ResourceData,
+	vpn awsVpnConnection) error {
 	client, err := p.grpcClient()
 	if err != nil {
 		log.Printf("AddAwsVpnConfig: Failed to create new CVaaS Grpc client, err: %v", err)
 		return err
 	}
 
-	defer client.Close()
 	awsVpnClient := api.NewAWSVpnConfigServiceClient(client)
 
 	var tunnels []*api.TunnelInfo
-	var tunnel1 api.TunnelInfo
-	var tunnel2 api.TunnelInfo
-	tunnel1Endpoint := d.Get("tunnel1_aws_endpoint_ip").(string)
-	tunnel2Endpoint := d.Get("tunnel2_aws_endpoint_ip").(string)
-	tunnel1RouterTunnelIp := d.Get("tunnel1_router_overlay_ip").(string)
-	tunnel2RouterTunnelIp := d.Get("tunnel2_router_overlay_ip").(string)
-	tunnel1BgpAsn := d.Get("tunnel1_bgp_asn").(string)
-	tunnel2BgpAsn := d.Get("tunnel2_bgp_asn").(string)
-	tunnel1AwsTunnelIp := d.Get("tunnel1_aws_overlay_ip").(string)
-	tunnel2AwsTunnelIp := d.Get("tunnel2_aws_overlay_ip").(string)
-	tunnel1BgpHoldTime := d.Get("tunnel1_bgp_holdtime").(string)
-	tunnel2BgpHoldTime := d.Get("tunnel2_bgp_holdtime").(string)
-	tunnel1PresharedKey := d.Get("tunnel1_preshared_key").(string)
-	tunnel2PresharedKey := d.Get("tunnel2_preshared_key").(string)
-	tunnel1.TunnelAwsEndpointIp = &fmp.IPAddress{Value: tunnel1Endpoint}
-	tunnel2.TunnelAwsEndpointIp = &fmp.IPAddress{Value: tunnel2Endpoint}
-	tunnel1.TunnelBgpAsn = &wrappers.StringValue{Value: tunnel1BgpAsn}
-	tunnel2.TunnelBgpAsn = &wrappers.StringValue{Value: tunnel2BgpAsn}
-	tunnel1.TunnelRouterOverlayIp = &fmp.IPAddress{Value: tunnel1RouterTunnelIp}
-	tunnel2.TunnelRouterOverlayIp = &fmp.IPAddress{Value: tunnel2RouterTunnelIp}
-	tunnel1.TunnelAwsOverlayIp = &fmp.IPAddress{Value: tunnel1AwsTunnelIp}
-	tunnel2.TunnelAwsOverlayIp = &fmp.IPAddress{Value: tunnel2AwsTunnelIp}
-	tunnel1.TunnelBgpHoldtime = &wrappers.StringValue{Value: tunnel1BgpHoldTime}
-	tunnel2.TunnelBgpHoldtime = &wrappers.StringValue{Value: tunnel2BgpHoldTime}
-	tunnel1.TunnelPresharedKey = &wrappers.StringValue{Value: tunnel1PresharedKey}
-	tunnel2.TunnelPresharedKey = &wrappers.StringValue{Value: tunnel2PresharedKey}
-	//Ipsec Info is default and we aren't passing that for now
-
-	tunnels = append(tunnels, &tunnel1)
-	tunnels = append(tunnels, &tunnel2)
+	for _, t := range vpn.tunnels {
+		tunnel := t.(map[string]interface{})
+		tunnels = append(tunnels, &api.TunnelInfo{
+			TunnelAwsEndpointIp:   &fmp.IPAddress{Value: tunnel["aws_endpoint_ip"].(string)},
+			TunnelBgpAsn:          &wrappers.StringValue{Value: tunnel["bgp_asn"].(string)},
+			TunnelRouterOverlayIp: &fmp.IPAddress{Value: tunnel["router_overlay_ip"].(string)},
+			TunnelAwsOverlayIp:    &fmp.IPAddress{Value: tunnel["aws_overlay_ip"].(string)},
+			TunnelBgpHoldtime:     &wrappers.StringValue{Value: tunnel["bgp_holdtime"].(string)},
+			TunnelPresharedKey:    &wrappers.StringValue{Value: tunnel["preshared_key"].(string)},
+			// CVaaS uses the AWS defaults when the Ipsec Info isn't set
+			IpsecInfo: getIpsecInfo(tunnel["ipsec"].([]interface{})),
+		})
+	}
 
 	tunnelInfoList := &api.TunnelInfoList{
 		Values: tunnels,
@@ -90,12 +159,10 @@ func (p *CloudeosProvider) AddAwsVpnConfig(d *schema.ResourceData) error {
 	awsVpnKey := &api.AWSVpnKey{
 		TfId: &wrappers.StringValue{Value: d.Get("tf_id").(string)},
 	}
-	tgwId := d.Get("tgw_id").(string)
-	vpnConnectionId := d.Get("vpn_connection_id").(string)
 	awsVpnConfigInfo := &api.AWSVpnConfig{
 		Key:                awsVpnKey,
-		TgwId:              &wrappers.StringValue{Value: tgwId},
-		VpnConnectionId:    &wrappers.StringValue{Value: vpnConnectionId},
+		TgwId:              &wrappers.StringValue{Value: vpn.tgwID},
+		VpnConnectionId:    &wrappers.StringValue{Value: vpn.vpnConnectionID},
 		CgwId:              &wrappers.StringValue{Value: d.Get("cgw_id").(string)},
 		CloudeosRouterId:   &wrappers.StringValue{Value: d.Get("router_id").(string)},
 		CloudeosVpcId:      &wrappers.StringValue{Value: d.Get("vpc_id").(string)},
@@ -109,12 +176,14 @@ func (p *CloudeosProvider) AddAwsVpnConfig(d *schema.ResourceData) error {
 		Value: awsVpnConfigInfo,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] AddAwsVpnConfigRequest: %v", redact(&awsVpnConfigSetRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := awsVpnClient.Set(ctx, &awsVpnConfigSetRequest)
 	if err != nil && resp == nil {
 		return err
 	}
+	log.Printf("[CVaaS-INFO] AddAwsVpnConfigResponse: %v", redact(resp))
 
 	value := resp.Value
 	if value != nil && value.GetKey() != nil && value.GetKey().GetTfId() != nil {
@@ -123,3 +192,170 @@ func (p *CloudeosProvider) AddAwsVpnConfig(d *schema.ResourceData) error {
 	}
 	return nil
 }
+
+// getIpsecInfo returns the Ipsec Info of a tunnel ipsec block, or nil when the
+// block isn't set. Parameters which aren't set are left to their AWS default.
+func getIpsecInfo(ipsecList []interface{}) *api.IpsecInfo {
+	if len(ipsecList) == 0 || ipsecList[0] == nil {
+		return nil
+	}
+	ipsec := ipsecList[0].(map[string]interface{})
+	stringValue := func(attr string) *wrappers.StringValue {
+		var value string
+		switch v := ipsec[attr].(type) {
+		case string:
+			value = v
+		case int:
+			if v != 0 {
+				value = strconv.Itoa(v)
+			}
+		}
+		if value == "" {
+			return nil
+		}
+		return &wrappers.StringValue{Value: value}
+	}
+	// The preshared key is the one of the tunnel
+	return &api.IpsecInfo{
+		IkeIntegrity:    stringValue("ike_integrity"),
+		IkeEncryption:   stringValue("ike_encryption"),
+		IkeLifetime:     stringValue("ike_lifetime"),
+		IkePfs:          stringValue("ike_pfs"),
+		IpsecIntegrity:  stringValue("ipsec_integrity"),
+		IpsecEncryption: stringValue("ipsec_encryption"),
+		IpsecPfs:        stringValue("ipsec_pfs"),
+		IpsecMode:       stringValue("ipsec_mode"),
+		DpdInterval:     stringValue("dpd_interval"),
+		DpdRetries:      stringValue("dpd_retries"),
+	}
+}
+
+func (p *CloudeosProvider) GetAwsTgw(tgwID string) (*api.AWSTgw, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("GetAwsTgw: Failed to create new CVaaS Grpc client, err: %v", err)
+		return nil, err
+	}
+
+	awsTgwClient := api.NewAWSTgwServiceClient(client)
+	awsTgwRequest := api.AWSTgwRequest{
+		Key: &api.AWSTgwKey{TgwId: &wrappers.StringValue{Value: tgwID}},
+	}
+
+	log.Printf("[CVaaS-INFO] GetAwsTgwRequest: %v", redact(&awsTgwRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	resp, err := awsTgwClient.GetOne(ctx, &awsTgwRequest)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[CVaaS-INFO] Received GetAwsTgwResponse: %v", redact(resp))
+
+	if resp.GetValue().GetKey().GetTgwId().GetValue() == "" {
+		return nil, fmt.Errorf("No aws tgw entry found for tgw_id %s", tgwID)
+	}
+	return resp.GetValue(), nil
+}
+
+// GetAllAwsTgw returns the TGWs matching the fields set in filter
+func (p *CloudeosProvider) GetAllAwsTgw(filter *api.AWSTgw) ([]*api.AWSTgw, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("GetAllAwsTgw: Failed to create new CVaaS Grpc client, err: %v", err)
+		return nil, err
+	}
+
+	awsTgwClient := api.NewAWSTgwServiceClient(client)
+	awsTgwStreamRequest := api.AWSTgwStreamRequest{
+		PartialEqFilter: []*api.AWSTgw{filter},
+	}
+
+	log.Printf("[CVaaS-INFO] GetAllAwsTgwRequest: %v", redact(&awsTgwStreamRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	stream, err := awsTgwClient.GetAll(ctx, &awsTgwStreamRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	var tgws []*api.AWSTgw
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading grpc stream: %v", err)
+		}
+		tgws = append(tgws, resp.GetValue())
+	}
+	return tgws, nil
+}
+
+func (p *CloudeosProvider) GetAwsTgwAttachment(attachmentID string) (*api.AWSTgwAttachment,
+	error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("GetAwsTgwAttachment: Failed to create new CVaaS Grpc client, err: %v", err)
+		return nil, err
+	}
+
+	attachmentClient := api.NewAWSTgwAttachmentServiceClient(client)
+	attachmentRequest := api.AWSTgwAttachmentRequest{
+		Key: &api.AWSTgwAttachmentKey{
+			AttachmentId: &wrappers.StringValue{Value: attachmentID},
+		},
+	}
+
+	log.Printf("[CVaaS-INFO] GetAwsTgwAttachmentRequest: %v", redact(&attachmentRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	resp, err := attachmentClient.GetOne(ctx, &attachmentRequest)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[CVaaS-INFO] Received GetAwsTgwAttachmentResponse: %v", redact(resp))
+
+	if resp.GetValue().GetKey().GetAttachmentId().GetValue() == "" {
+		return nil, fmt.Errorf("No aws tgw attachment entry found for attachment_id %s",
+			attachmentID)
+	}
+	return resp.GetValue(), nil
+}
+
+// GetAllAwsTgwAttachment returns the TGW attachments matching the fields set
+// in filter
+func (p *CloudeosProvider) GetAllAwsTgwAttachment(filter *api.AWSTgwAttachment) (
+	[]*api.AWSTgwAttachment, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("GetAllAwsTgwAttachment: Failed to create new CVaaS Grpc client, err: %v", err)
+		return nil, err
+	}
+
+	attachmentClient := api.NewAWSTgwAttachmentServiceClient(client)
+	attachmentStreamRequest := api.AWSTgwAttachmentStreamRequest{
+		PartialEqFilter: []*api.AWSTgwAttachment{filter},
+	}
+
+	log.Printf("[CVaaS-INFO] GetAllAwsTgwAttachmentRequest: %v", redact(&attachmentStreamRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	stream, err := attachmentClient.GetAll(ctx, &attachmentStreamRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []*api.AWSTgwAttachment
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading grpc stream: %v", err)
+		}
+		attachments = append(attachments, resp.GetValue())
+	}
+	return attachments, nil
+}
@@ -6,15 +6,19 @@ package cloudeos
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
@@ -23,9 +27,14 @@ import (
 
 	cvgrpc "github.com/aristanetworks/cloudvision-go/grpc"
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/backoffutils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -42,11 +51,27 @@ const (
 	AwsVpnPrefix = "ar-aws-vpn"
 )
 
+// Defaults for the provider request timeout and retry settings
 const (
-	// Retry attempts for grpc connect
-	CVaaSRetryCount = 5
-	// Time limit for request timeout 3 min
-	requestTimeout = 180
+	// Port of cvaas_server when it doesn't specify one
+	defaultCVaaSPort = "443"
+	// Time limit for a single request to CVaaS, including its retries
+	defaultRequestTimeout = 180 * time.Second
+	// Attempts made for a request failing with a retryable code
+	defaultRetryMaxAttempts = 5
+	// Backoff between attempts grows exponentially from the base up to the cap
+	defaultRetryBackoffBase = 500 * time.Millisecond
+	defaultRetryBackoffCap  = 30 * time.Second
+	// Fraction of the backoff randomly added or removed on each attempt
+	defaultRetryBackoffJitter = 0.2
+)
+
+// Keepalive settings for the CVaaS connection. The ping interval is kept at
+// the grpc server default enforcement minimum so that CVaaS doesn't close
+// the connection for pinging too often.
+const (
+	grpcKeepaliveTime    = 5 * time.Minute
+	grpcKeepaliveTimeout = 20 * time.Second
 )
 
 //CloudeosProvider configuration
@@ -54,134 +79,574 @@ type CloudeosProvider struct {
 	srvcAcctToken string
 	server        string
 	cvaasDomain   string
+	// tlsConfig is set when using a custom CA / client certificate
+	tlsConfig *tls.Config
+	// insecure disables TLS, for development against a local server
+	insecure bool
+	// requestTimeout bounds every request to CVaaS
+	requestTimeout time.Duration
+	retry          retryPolicy
+	// deadline, if set, bounds all the requests of a resource operation
+	deadline time.Time
+	// conn is shared by all copies of the provider, so that every resource
+	// reuses the same connection to CVaaS
+	conn *grpcConn
+	// assignment is shared like conn, so that the regional redirector is
+	// only queried once
+	assignment *assignment
+}
+
+// retryPolicy describes how requests failing with a retryable code are retried
+type retryPolicy struct {
+	maxAttempts   uint
+	backoffBase   time.Duration
+	backoffCap    time.Duration
+	backoffJitter float64
+	codes         []codes.Code
+}
+
+func defaultRetryPolicy() retryPolicy {
+	return retryPolicy{
+		maxAttempts:   defaultRetryMaxAttempts,
+		backoffBase:   defaultRetryBackoffBase,
+		backoffCap:    defaultRetryBackoffCap,
+		backoffJitter: defaultRetryBackoffJitter,
+		codes:         []codes.Code{codes.Unavailable},
+	}
+}
+
+// backoff returns the time to wait before the given attempt, doubling the
+// base for every attempt up to the cap, with jitter applied
+func (r retryPolicy) backoff(attempt uint) time.Duration {
+	wait := r.backoffCap
+	// Past 30 doublings any sane base is already above the cap
+	if attempt < 30 {
+		if exp := r.backoffBase * time.Duration(backoffutils.ExponentBase2(attempt)); exp < wait {
+			wait = exp
+		}
+	}
+	return backoffutils.JitterUp(wait, r.backoffJitter)
+}
+
+func (r retryPolicy) callOptions() []grpc_retry.CallOption {
+	return []grpc_retry.CallOption{
+		grpc_retry.WithMax(r.maxAttempts),
+		grpc_retry.WithBackoff(r.backoff),
+		grpc_retry.WithCodes(r.codes...),
+	}
+}
+
+// setTimeout bounds all the requests made through this copy of the provider
+// to complete within timeout from now. Resources call it with d.Timeout so
+// that a whole operation honours the timeout of the resource.
+func (p *CloudeosProvider) setTimeout(timeout time.Duration) {
+	p.deadline = time.Now().Add(timeout)
 }
 
+// requestContext returns the context for a single request to CVaaS
+func (p *CloudeosProvider) requestContext() (context.Context, context.CancelFunc) {
+	timeout := p.requestTimeout
+	if timeout == 0 {
+		timeout = defaultRequestTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	if !p.deadline.IsZero() && p.deadline.Before(deadline) {
+		deadline = p.deadline
+	}
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+// grpcConn is a lazily created connection to CVaaS
+type grpcConn struct {
+	mu sync.Mutex
+	cc *grpc.ClientConn
+}
+
+// get returns the current connection, dialing a new one if there is none yet
+// or if the current one has failed
+func (c *grpcConn) get(dial func() (*grpc.ClientConn, error)) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cc != nil {
+		switch state := c.cc.GetState(); state {
+		case connectivity.Shutdown, connectivity.TransientFailure:
+			log.Printf("[CVaaS-INFO] Connection to CVaaS in state %s, reconnecting", state)
+			c.cc.Close()
+			c.cc = nil
+		default:
+			return c.cc, nil
+		}
+	}
+
+	cc, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	c.cc = cc
+	return c.cc, nil
+}
+
+func (c *grpcConn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cc == nil {
+		return nil
+	}
+	err := c.cc.Close()
+	c.cc = nil
+	return err
+}
+
+// Close closes the connection to CVaaS, if one was opened
+func (p *CloudeosProvider) Close() error {
+	if p.conn == nil {
+		return nil
+	}
+	return p.conn.close()
+}
+
+// grpcClient returns the connection to CVaaS shared by all resources. The
+// connection must not be closed by the caller.
 func (p *CloudeosProvider) grpcClient() (*grpc.ClientConn, error) {
-	opts := []grpc_retry.CallOption{
-		grpc_retry.WithMax(5),
-		grpc_retry.WithBackoff(grpc_retry.BackoffExponential(500 * time.Millisecond)),
-		grpc_retry.WithCodes(codes.Unavailable),
+	if p.conn == nil {
+		p.conn = &grpcConn{}
 	}
+	return p.conn.get(p.dial)
+}
 
-	return cvgrpc.DialWithToken(context.Background(), p.server+":443", p.srvcAcctToken,
+// grpcTarget returns cvaas_server with the default port appended if it
+// doesn't specify one
+func (p *CloudeosProvider) grpcTarget() string {
+	return grpcHostTarget(p.server)
+}
+
+// customTransport is true when the provider isn't using the default TLS
+// setup of CVaaS, i.e. it has a custom CA / client certificate or runs in
+// plaintext. Regional redirection is skipped in that case, as it is only
+// served by CVaaS.
+func (p *CloudeosProvider) customTransport() bool {
+	return p.insecure || p.tlsConfig != nil
+}
+
+// grpcHostTarget returns host with the default port appended if it doesn't
+// specify one
+func grpcHostTarget(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, defaultCVaaSPort)
+}
+
+// tokenDialOptions returns the options to connect to CVaaS with the service
+// account token, over TLS unless insecure is set
+func (p *CloudeosProvider) tokenDialOptions() []grpc.DialOption {
+	var creds credentials.TransportCredentials
+	switch {
+	case p.insecure:
+		creds = insecure.NewCredentials()
+	case p.tlsConfig != nil:
+		creds = credentials.NewTLS(p.tlsConfig)
+	default:
+		creds = credentials.NewTLS(cvgrpc.TLSConfig())
+	}
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithBlock(),
+		grpc.WithPerRPCCredentials(&tokenCredential{
+			token:      p.srvcAcctToken,
+			requireTLS: !p.insecure,
+		}),
+	}
+}
+
+// dial connects to the first reachable host of the regional assignment
+func (p *CloudeosProvider) dial() (*grpc.ClientConn, error) {
+	retry := p.retry
+	if retry.maxAttempts == 0 {
+		retry = defaultRetryPolicy()
+	}
+	opts := retry.callOptions()
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    grpcKeepaliveTime,
+			Timeout: grpcKeepaliveTimeout,
+		}),
 		grpc.WithStreamInterceptor(grpc_retry.StreamClientInterceptor(opts...)),
-		grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(opts...)))
+		grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(opts...)),
+	}, p.tokenDialOptions()...)
 
+	var cc *grpc.ClientConn
+	err := p.onAssignedHost(func(host string) error {
+		ctx, cancel := p.requestContext()
+		defer cancel()
+		var err error
+		cc, err = grpc.DialContext(ctx, grpcHostTarget(host), dialOpts...)
+		return err
+	})
+	return cc, err
+}
+
+// tokenCredential passes the service account token with every request. Unlike
+// the cloudvision-go credential, it can be used over a plaintext connection.
+type tokenCredential struct {
+	token      string
+	requireTLS bool
 }
 
-func (p *CloudeosProvider) httpClient() (*http.Client, error) {
-	return &http.Client{}, nil
+func (t *tokenCredential) GetRequestMetadata(ctx context.Context,
+	uri ...string) (map[string]string, error) {
+	return map[string]string{
+		"Authorization": "Bearer " + t.token,
+	}, nil
 }
 
-func (p *CloudeosProvider) getAssignment(target string) (string, error) {
-	if strings.ToLower(os.Getenv("CLOUDVISION_REGIONAL_REDIRECT")) == "false" {
-		return target, nil
+func (t *tokenCredential) RequireTransportSecurity() bool {
+	return t.requireTLS
+}
+
+// newTLSConfig returns the TLS configuration for a CVaaS endpoint using a
+// custom CA bundle and / or client certificate
+func newTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	tlsConfig := cvgrpc.TLSConfig()
+	if caFile != "" {
+		caPem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read ca_file %q: %s", caFile, err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("No PEM certificates found in ca_file %q", caFile)
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, errors.New("client_cert_file and client_key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
+	return tlsConfig, nil
+}
 
-        client, err := p.httpClient()
-        if err != nil {
-                return "", err
-        }
+// httpURL returns the url of a CVaaS REST endpoint on server
+func (p *CloudeosProvider) httpURL(server, path string) string {
+	scheme := "https"
+	if p.insecure {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, server, path)
+}
 
-        url := fmt.Sprintf("https://%s/api/v3/services/arista.redirector.v1.AssignmentService/GetOne", target)
-        requestBody := strings.NewReader(`{"key":{"system_id":"*"}}`)
-        req, err := http.NewRequest("POST", url, requestBody)
-        if err != nil {
-                return "", err
-        }
+// httpClient returns the client of the CVaaS REST requests, with the TLS
+// configuration of the gRPC connection
+func (p *CloudeosProvider) httpClient() *http.Client {
+	// The default transport takes the proxy from HTTPS_PROXY / NO_PROXY
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = p.tlsConfig
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = cvgrpc.TLSConfig()
+	}
+	return &http.Client{Transport: transport}
+}
 
-        var bearer = "Bearer " + p.srvcAcctToken
-        req.Header.Add("Authorization", bearer)
-	resp, err := client.Do(req)
+// httpError is returned when a CVaaS REST endpoint replies with a non 2xx
+// status
+type httpError struct {
+	method     string
+	url        string
+	statusCode int
+	body       string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("%s %s failed with status %d %s: %s", e.method, e.url,
+		e.statusCode, http.StatusText(e.statusCode), e.body)
+}
+
+// retryableHTTPStatus reports whether a request failing with the given status
+// may succeed when retried
+func retryableHTTPStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// httpDo sends a request to a CVaaS REST endpoint and returns the response
+// body. Like the gRPC requests it is bounded by the request timeout, and
+// transient failures are retried with the provider retry policy.
+func (p *CloudeosProvider) httpDo(method, url, body string) ([]byte, error) {
+	client := p.httpClient()
+	retry := p.retry
+	if retry.maxAttempts == 0 {
+		retry = defaultRetryPolicy()
+	}
+	ctx, cancel := p.requestContext()
+	defer cancel()
+
+	for attempt := uint(1); ; attempt++ {
+		respBody, err := p.httpDoOnce(ctx, client, method, url, body)
+		if err == nil {
+			return respBody, nil
+		}
+		var httpErr *httpError
+		if errors.As(err, &httpErr) && !retryableHTTPStatus(httpErr.statusCode) {
+			return nil, err
+		}
+		if attempt >= retry.maxAttempts {
+			return nil, err
+		}
+		log.Printf("[CVaaS-INFO] %s %s failed, retrying: %s", method, url, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(retry.backoff(attempt)):
+		}
+	}
+}
+
+func (p *CloudeosProvider) httpDoOnce(ctx context.Context, client *http.Client,
+	method, url, body string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	req.Header.Add("Authorization", "Bearer "+p.srvcAcctToken)
+	req.Header.Add("Content-Type", "application/json")
 
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("Error while reading the response of %s %s: %v", method, url, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &httpError{
+			method:     method,
+			url:        url,
+			statusCode: resp.StatusCode,
+			body:       strings.TrimSpace(string(respBody)),
+		}
+	}
+	return respBody, nil
+}
+
+// assignedHost is a CVaaS host of the clusters the service account is
+// assigned to by the regional redirector
+type assignedHost struct {
+	cluster string
+	host    string
+}
+
+// assignment caches the hosts the service account is assigned to, so that the
+// redirector is only queried once for the lifetime of the provider
+type assignment struct {
+	mu    sync.Mutex
+	hosts []assignedHost
+	// current is the index of the last host which could be reached
+	current int
+}
+
+// assignedHosts returns the assigned hosts, resolving them on first use, along
+// with the index of the host to try first
+func (p *CloudeosProvider) assignedHosts() ([]assignedHost, int, error) {
+	if p.assignment == nil {
+		p.assignment = &assignment{}
 	}
+	a := p.assignment
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-        var intfs []interface{}
-        err = json.Unmarshal([]byte(body), &intfs)
-        if err != nil {
-                return "", fmt.Errorf("Failed to unmarshal to interface: %v", err)
-        }
-
-        aResp := &rdr.AssignmentResponse{}
-        bytes, err := json.Marshal(intfs[0])
-        if err != nil {
-                return "", fmt.Errorf("Failed to marshal interface: %v", err)
-        }
-        opts := &protojson.UnmarshalOptions{AllowPartial: true, DiscardUnknown: true}
-        err = opts.Unmarshal(bytes, aResp)
-        if err != nil {
-                return "", fmt.Errorf("Failed to unmarshal with protojson: %v", err)
-        }
-
-	fmt.Printf("Clusters returned %+v", aResp.Value.Clusters.Values)
-        for _, vals := range aResp.Value.Clusters.Values {
-                for _, host := range vals.Hosts.Values {
-                        return host, nil
-                }
-        }
-        return "", fmt.Errorf("No assignment found for service account token")
-}
-
-func (p *CloudeosProvider) getDeviceEnrollmentToken() (string, error) {
-	server, err := p.getAssignment(p.server)
-	if err != nil || server == "" {
-		return "", fmt.Errorf("Failed to get server assignment: %s", err)
-	}
-
-	url := fmt.Sprintf("https://%s/api/resources/admin.Enrollment/AddEnrollmentToken",
-		strings.Split(server, ":")[0])
-	var bearer = "Bearer " + p.srvcAcctToken
-
-	// Create a new request using http
-	requestBody := strings.NewReader(`{
-		"enrollmentToken":{
-			"reenrollDevices":["*"],
-			"validFor":"7200s",
-			"groups":[]}}
-	`)
-
-	req, err := http.NewRequest("POST", url, requestBody)
+	if a.hosts == nil {
+		hosts, err := p.getAssignment()
+		if err != nil {
+			return nil, 0, err
+		}
+		a.hosts = hosts
+	}
+	return a.hosts, a.current, nil
+}
+
+// setCurrent records the host which could be reached, to be tried first by
+// later requests
+func (a *assignment) setCurrent(current int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.current = current
+}
+
+// onAssignedHost calls fn with the assigned hosts until it succeeds, starting
+// with the last host which could be reached and failing over to the other
+// hosts and clusters when a host is unreachable
+func (p *CloudeosProvider) onAssignedHost(fn func(host string) error) error {
+	hosts, current, err := p.assignedHosts()
 	if err != nil {
-		return "", errors.New("Error creating AddEnrollmentToken http request")
+		return err
 	}
 
-	// add authorization header to the req
-	req.Header.Add("Authorization", bearer)
-	client, err := p.httpClient()
+	for i := range hosts {
+		idx := (current + i) % len(hosts)
+		err = fn(hosts[idx].host)
+		if err == nil {
+			p.assignment.setCurrent(idx)
+			return nil
+		}
+		// The host replied, there is no point in asking another one
+		var httpErr *httpError
+		if errors.As(err, &httpErr) && !retryableHTTPStatus(httpErr.statusCode) {
+			return err
+		}
+		log.Printf("[CVaaS-INFO] CVaaS host %s of cluster %q unreachable: %s",
+			hosts[idx].host, hosts[idx].cluster, err)
+	}
+	return err
+}
+
+// getAssignment queries the regional redirector of cvaas_server for the hosts
+// of the clusters the service account is assigned to. When redirection is
+// disabled or not served, cvaas_server itself is the only host.
+func (p *CloudeosProvider) getAssignment() ([]assignedHost, error) {
+	noRedirect := []assignedHost{{host: p.server}}
+	if strings.ToLower(os.Getenv("CLOUDVISION_REGIONAL_REDIRECT")) == "false" ||
+		p.customTransport() {
+		return noRedirect, nil
+	}
+
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, p.grpcTarget(), p.tokenDialOptions()...)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("Failed to connect to %s for the regional assignment: %w",
+			p.server, err)
 	}
+	defer conn.Close()
 
-	resp, err := client.Do(req)
+	assignmentRequest := &rdr.AssignmentRequest{
+		Key: &rdr.AssignmentKey{SystemId: wrapperspb.String("*")},
+	}
+	log.Printf("[CVaaS-INFO] AssignmentRequest: %v", redact(assignmentRequest))
+	resp, err := rdr.NewAssignmentServiceClient(conn).GetOne(ctx, assignmentRequest)
+	if status.Code(err) == codes.Unimplemented {
+		return noRedirect, nil
+	}
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("Failed to get the regional assignment: %w", err)
 	}
+	log.Printf("[CVaaS-INFO] Received AssignmentResponse: %v", redact(resp))
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	var hosts []assignedHost
+	for _, cluster := range resp.GetValue().GetClusters().GetValues() {
+		for _, host := range cluster.GetHosts().GetValues() {
+			hosts = append(hosts, assignedHost{
+				cluster: cluster.GetName().GetValue(),
+				host:    host,
+			})
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("No assignment found for service account token")
+	}
+	return hosts, nil
+}
+
+// enrollmentToken is the EnrollmentToken of the CloudVision admin.Enrollment
+// service. cloudvision-go has no gRPC bindings for that service, so it is
+// reached through its REST endpoint.
+type enrollmentToken struct {
+	Token string `json:"token,omitempty"`
+	// Device groups the enrolled devices are added to
+	Groups []string `json:"groups"`
+	// Serial numbers of the devices allowed to re-enroll with the token, "*"
+	// allows any device
+	ReenrollDevices []string `json:"reenrollDevices"`
+	// Validity of the token, in seconds, e.g. 7200s
+	ValidFor string `json:"validFor,omitempty"`
+}
+
+type enrollmentTokenMessage struct {
+	EnrollmentToken *enrollmentToken `json:"enrollmentToken"`
+}
+
+// getDeviceEnrollmentToken creates a device enrollment token with the
+// AddEnrollmentToken REST endpoint. It isn't requested over gRPC like the other
+// CVaaS requests as cloudvision-go has no bindings for the admin.Enrollment
+// service yet.
+func (p *CloudeosProvider) getDeviceEnrollmentToken(token enrollmentToken) (string, error) {
+	// CloudVision expects lists, not nulls
+	if token.Groups == nil {
+		token.Groups = []string{}
+	}
+	if token.ReenrollDevices == nil {
+		token.ReenrollDevices = []string{}
+	}
+	requestBody, err := json.Marshal(&enrollmentTokenMessage{EnrollmentToken: &token})
 	if err != nil {
-		return "", errors.New(fmt.Sprintf("Error while reading the response bytes: %v", err))
+		return "", fmt.Errorf("Failed to marshal AddEnrollmentToken request: %v", err)
 	}
 
-	var data map[string]interface{}
-	err = json.Unmarshal([]byte(body), &data)
+	var body []byte
+	err = p.onAssignedHost(func(host string) error {
+		url := p.httpURL(host, "/api/resources/admin.Enrollment/AddEnrollmentToken")
+		body, err = p.httpDo("POST", url, string(requestBody))
+		return err
+	})
 	if err != nil {
-		return "", fmt.Errorf("Failed to get enrollment token : %s (%s)", err, body)
+		return "", fmt.Errorf("Failed to get enrollment token: %w", err)
 	}
 
-	enrollmentTokenMap, ok := data["enrollmentToken"].(map[string]interface{})
-	if !ok {
+	var resp enrollmentTokenMessage
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("Failed to unmarshal AddEnrollmentToken response: %v", err)
+	}
+	if resp.EnrollmentToken == nil || resp.EnrollmentToken.Token == "" {
 		return "", errors.New("Token key not found in AddEnrollmentToken response")
 	}
+	return resp.EnrollmentToken.Token, nil
+}
 
-	return enrollmentTokenMap["token"].(string), nil
+// getRouterEnrollmentToken returns the settings of the enrollment token of the
+// router. Unless the devices allowed to re-enroll are configured, the token
+// only allows the device of the router to re-enroll, if it already enrolled,
+// or no re-enrollment at all for a new router.
+func (p *CloudeosProvider) getRouterEnrollmentToken(d *schema.ResourceData) (enrollmentToken,
+	error) {
+	validFor, err := time.ParseDuration(d.Get("enrollment_token_valid_for").(string))
+	if err != nil {
+		return enrollmentToken{}, err
+	}
+	token := enrollmentToken{
+		ValidFor: fmt.Sprintf("%ds", int64(validFor.Seconds())),
+	}
+	for _, group := range d.Get("enrollment_token_groups").([]interface{}) {
+		token.Groups = append(token.Groups, group.(string))
+	}
+
+	if reenroll, ok := d.GetOk("enrollment_token_reenroll_devices"); ok {
+		for _, device := range reenroll.([]interface{}) {
+			token.ReenrollDevices = append(token.ReenrollDevices, device.(string))
+		}
+		return token, nil
+	}
+	if d.Id() != "" {
+		resp, err := p.GetRouterResponse(d)
+		if err != nil {
+			return enrollmentToken{}, err
+		}
+		if serial := resp.GetValue().GetDeviceSerialNum().GetValue(); serial != "" {
+			token.ReenrollDevices = []string{serial}
+		}
+	}
+	return token, nil
 }
 
 //IsValidTopoAddition checks if there already exists an entry in CVaaS by
@@ -195,7 +660,6 @@ func (p *CloudeosProvider) IsValidTopoAddition(d *schema.ResourceData,
 		return false, err
 	}
 
-	defer client.Close()
 	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
 	closName := ""
 	wanName := ""
@@ -216,10 +680,9 @@ func (p *CloudeosProvider) IsValidTopoAddition(d *schema.ResourceData,
 		PartialEqFilter: []*cdv1_api.TopologyInfoConfig{topoInfo},
 	}
 
-	log.Printf("[CVaaS-INFO] GetAllTopologyInfoRequest: %v", getAllTopoInfoRequest)
+	log.Printf("[CVaaS-INFO] GetAllTopologyInfoRequest: %v", redact(&getAllTopoInfoRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	stream, err := topoInfoClient.GetAll(ctx, &getAllTopoInfoRequest)
@@ -274,7 +737,6 @@ func (p *CloudeosProvider) AddVpcConfig(d *schema.ResourceData) error {
 		return err
 	}
 
-	defer client.Close()
 	vpcClient := cdv1_api.NewVpcConfigServiceClient(client)
 	vpcName, cpType := getCpTypeAndVpcName(d)
 	roleType := getRoleType(d.Get("role").(string))
@@ -299,9 +761,9 @@ func (p *CloudeosProvider) AddVpcConfig(d *schema.ResourceData) error {
 		Value: vpc,
 	}
 
-	log.Printf("[CVaaS-INFO] AddVpcRequest: %v", &addVpcRequest)
+	log.Printf("[CVaaS-INFO] AddVpcRequest: %v", redact(&addVpcRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := vpcClient.Set(ctx, &addVpcRequest)
 	if err != nil && resp == nil {
@@ -318,15 +780,15 @@ func (p *CloudeosProvider) AddVpcConfig(d *schema.ResourceData) error {
 	return nil
 }
 
-//GetVpc gets vpc which satisfy the filter
-func (p *CloudeosProvider) GetVpc(d *schema.ResourceData) error {
+//GetVpcResponse gets the vpc entry for the resource tf_id from Aeris
+func (p *CloudeosProvider) GetVpcResponse(d *schema.ResourceData) (*cdv1_api.VpcConfigResponse,
+	error) {
 	client, err := p.grpcClient()
 	if err != nil {
 		log.Printf("Failed to create new CVaaS Grpc client to execute GetVpc")
-		return err
+		return nil, err
 	}
 
-	defer client.Close()
 	vpcClient := cdv1_api.NewVpcConfigServiceClient(client)
 	vpcKey := &cdv1_api.VpcKey{
 		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
@@ -336,46 +798,23 @@ func (p *CloudeosProvider) GetVpc(d *schema.ResourceData) error {
 		Key: vpcKey,
 	}
 
-	log.Printf("[CVaaS-INFO] GetVpcRequest: %v", &getVpcRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] GetVpcRequest: %v", redact(&getVpcRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	resp, err := vpcClient.GetOne(ctx, &getVpcRequest)
-	log.Printf("Received GetVpc Resp: %v", resp)
+	log.Printf("[CVaaS-INFO] Received GetVpc Resp: %v", redact(resp))
+	return resp, err
+}
+
+//GetVpc gets vpc which satisfy the filter
+func (p *CloudeosProvider) GetVpc(d *schema.ResourceData) error {
+	resp, err := p.GetVpcResponse(d)
 	if err != nil && resp == nil {
 		return err
 	}
 
-	if peerVpcInfo := resp.GetValue().GetPeerVpcInfo(); peerVpcInfo != nil {
-		if err = d.Set("peer_rg_name", peerVpcInfo.GetPeerRgName().GetValue()); err != nil {
-			return err
-		}
-
-		if err = d.Set("peer_vnet_name", peerVpcInfo.GetPeerVnetName().GetValue()); err != nil {
-			return err
-		}
-
-		if err = d.Set("peer_vnet_id", peerVpcInfo.GetPeerVnetId().GetValue()); err != nil {
-			return err
-		}
-
-		peerVpcCidrInfoMap := peerVpcInfo.GetPeerVpcCidr().GetValues()
-		for k := range peerVpcCidrInfoMap {
-			if err = d.Set("peer_vpc_id", k); err != nil {
-				return err
-			}
-
-			if err = d.Set("peervpcidr", peerVpcCidrInfoMap[k]); err != nil {
-				return err
-			}
-
-			if err = d.Set("peer_vpc_cidr", peerVpcCidrInfoMap[k]); err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return parsePeerVpcInfo(resp.GetValue(), d)
 }
 
 //CheckVpcDeletionStatus returns nil if Vpc doesn't exist
@@ -386,7 +825,6 @@ func (p *CloudeosProvider) CheckVpcDeletionStatus(d *schema.ResourceData) error
 		return err
 	}
 
-	defer client.Close()
 	vpcClient := cdv1_api.NewVpcConfigServiceClient(client)
 	vpcKey := &cdv1_api.VpcKey{
 		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
@@ -396,8 +834,8 @@ func (p *CloudeosProvider) CheckVpcDeletionStatus(d *schema.ResourceData) error
 		Key: vpcKey,
 	}
 
-	log.Printf("[CVaaS-INFO] GetVpcRequest: %v", &getVpcRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] GetVpcRequest: %v", redact(&getVpcRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	resp, err := vpcClient.GetOne(ctx, &getVpcRequest)
@@ -405,7 +843,7 @@ func (p *CloudeosProvider) CheckVpcDeletionStatus(d *schema.ResourceData) error
 		return err
 	}
 
-	log.Printf("Received GetVpc Resp: %v", resp)
+	log.Printf("[CVaaS-INFO] Received GetVpc Resp: %v", redact(resp))
 	vpcExists := false
 
 	// as we are returning an empty resource in case of no objects in aeris
@@ -432,7 +870,6 @@ func (p *CloudeosProvider) CheckVpcPresenceAndGetDeployMode(
 		return "", err
 	}
 
-	defer client.Close()
 	vpcClient := cdv1_api.NewVpcConfigServiceClient(client)
 	vpcID := d.Get("vpc_id").(string)
 	cpType := getCloudProviderType(d)
@@ -446,8 +883,8 @@ func (p *CloudeosProvider) CheckVpcPresenceAndGetDeployMode(
 		PartialEqFilter: []*cdv1_api.VpcConfig{vpc},
 	}
 
-	log.Printf("[CVaaS-INFO] GetAllVpcRequest : %v", getAllVpcRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] GetAllVpcRequest : %v", redact(getAllVpcRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	stream, err := vpcClient.GetAll(ctx, getAllVpcRequest)
@@ -479,6 +916,50 @@ func (p *CloudeosProvider) CheckVpcPresenceAndGetDeployMode(
 	return "", errors.New("No response for GetAllVpc")
 }
 
+//GetVpcByVpcID gets the vpc entry for the given cloud vpc_id
+func (p *CloudeosProvider) GetVpcByVpcID(vpcID string) (*cdv1_api.VpcConfig, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("Failed to create new CVaaS Grpc client to execute GetVpcByVpcID")
+		return nil, err
+	}
+
+	vpcClient := cdv1_api.NewVpcConfigServiceClient(client)
+	vpc := &cdv1_api.VpcConfig{
+		VpcId: &wrapperspb.StringValue{Value: vpcID},
+	}
+
+	getAllVpcRequest := &cdv1_api.VpcConfigStreamRequest{
+		PartialEqFilter: []*cdv1_api.VpcConfig{vpc},
+	}
+
+	log.Printf("[CVaaS-INFO] GetAllVpcRequest : %v", redact(getAllVpcRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+
+	stream, err := vpcClient.GetAll(ctx, getAllVpcRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading grpc stream: %v", err)
+		}
+
+		if resp.GetValue().GetVpcId().GetValue() == vpcID {
+			return resp.GetValue(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("No vpc entry found for vpc_id %s", vpcID)
+}
+
 //AddVpc adds VPC resource to Aeris
 func (p *CloudeosProvider) AddVpc(d *schema.ResourceData) error {
 	client, err := p.grpcClient()
@@ -487,7 +968,6 @@ func (p *CloudeosProvider) AddVpc(d *schema.ResourceData) error {
 		return err
 	}
 
-	defer client.Close()
 	vpcClient := cdv1_api.NewVpcConfigServiceClient(client)
 	roleType := getRoleType(d.Get("role").(string))
 	vpcName, cpType := getCpTypeAndVpcName(d)
@@ -529,15 +1009,18 @@ func (p *CloudeosProvider) AddVpc(d *schema.ResourceData) error {
 			Cidr:          &wrapperspb.StringValue{Value: d.Get("cidr_block").(string)},
 		}
 		vpc.AzVnetInfo = &azrVnetInfo
+	case strings.EqualFold("gcp", cloudProvider):
+		// There's no GCP vpc info in the API yet, so the cidr and the security
+		// group of GCP networks aren't stored in CVaaS
 	}
 
 	addVpcRequest := cdv1_api.VpcConfigSetRequest{
 		Value: vpc,
 	}
 
-	log.Printf("[CVaaS-INFO] AddVpcRequest: %v", &addVpcRequest)
+	log.Printf("[CVaaS-INFO] AddVpcRequest: %v", redact(&addVpcRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	resp, err := vpcClient.Set(ctx, &addVpcRequest)
@@ -556,7 +1039,6 @@ func (p *CloudeosProvider) DeleteVpc(d *schema.ResourceData) error {
 		return err
 	}
 
-	defer client.Close()
 	vpcClient := cdv1_api.NewVpcConfigServiceClient(client)
 	vpcKey := cdv1_api.VpcKey{
 		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
@@ -566,9 +1048,9 @@ func (p *CloudeosProvider) DeleteVpc(d *schema.ResourceData) error {
 		Key: &vpcKey,
 	}
 
-	log.Printf("[CVaaS-INFO] DeleteVpcRequest: %v", &delVpcRequest)
+	log.Printf("[CVaaS-INFO] DeleteVpcRequest: %v", redact(&delVpcRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	resp, err := vpcClient.Delete(ctx, &delVpcRequest)
@@ -588,7 +1070,6 @@ func (p *CloudeosProvider) ValidateTopoInfoAndGetDeployMode(
 		return "", err
 	}
 
-	defer client.Close()
 	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
 	topoName := d.Get("topology_name").(string)
 	closName := d.Get("clos_name").(string)
@@ -602,9 +1083,8 @@ func (p *CloudeosProvider) ValidateTopoInfoAndGetDeployMode(
 		PartialEqFilter: []*cdv1_api.TopologyInfoConfig{topoInfo},
 	}
 
-	log.Printf("[CVaaS-INFO] GetAllTopologyInfoRequest: %v", &GetAllTopoInfoRequest)
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] GetAllTopologyInfoRequest: %v", redact(&GetAllTopoInfoRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	stream, err := topoInfoClient.GetAll(ctx, &GetAllTopoInfoRequest)
@@ -701,7 +1181,6 @@ func (p *CloudeosProvider) CheckTopologyDeletionStatus(d *schema.ResourceData) e
 		log.Printf("Failed to create new CVaaS Grpc client to execute CheckTopologyDeletionStatus")
 		return err
 	}
-	defer client.Close()
 	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
 	topoInfoKey := cdv1_api.TopologyInfoKey{
 		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
@@ -711,10 +1190,9 @@ func (p *CloudeosProvider) CheckTopologyDeletionStatus(d *schema.ResourceData) e
 		Key: &topoInfoKey,
 	}
 
-	log.Printf("[CVaaS-INFO] GetTopologyInfoRequest: %v", getTopoInfoRequest)
+	log.Printf("[CVaaS-INFO] GetTopologyInfoRequest: %v", redact(&getTopoInfoRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := topoInfoClient.GetOne(ctx, &getTopoInfoRequest)
 	if err != nil && resp == nil {
@@ -735,6 +1213,92 @@ func (p *CloudeosProvider) CheckTopologyDeletionStatus(d *schema.ResourceData) e
 	return nil
 }
 
+//GetTopologyInfo gets the topology info entry for the resource tf_id from Aeris
+func (p *CloudeosProvider) GetTopologyInfo(d *schema.ResourceData) (*cdv1_api.TopologyInfoConfig,
+	error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("Failed to create new CVaaS Grpc client to execute GetTopologyInfo")
+		return nil, err
+	}
+	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
+	topoInfoKey := cdv1_api.TopologyInfoKey{
+		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
+	}
+
+	getTopoInfoRequest := cdv1_api.TopologyInfoConfigRequest{
+		Key: &topoInfoKey,
+	}
+
+	log.Printf("[CVaaS-INFO] GetTopologyInfoRequest: %v", redact(&getTopoInfoRequest))
+
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	resp, err := topoInfoClient.GetOne(ctx, &getTopoInfoRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[CVaaS-INFO] Received GetTopologyInfoResponse: %v", redact(resp))
+	// In case of object not existing in aeris, the server returns an empty
+	// TopologyInfoConfig, i.e with an empty key
+	return resp.GetValue(), nil
+}
+
+//GetTopologyInfoByName gets the topology info entry of the given type with
+//the given topology name. For wan and clos topologies, name is the wan or
+//clos name.
+func (p *CloudeosProvider) GetTopologyInfoByName(topoName, name string,
+	topoType cdv1_api.TopologyInfoType) (*cdv1_api.TopologyInfoConfig, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("Failed to create new CVaaS Grpc client to execute GetTopologyInfoByName")
+		return nil, err
+	}
+	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
+	topoInfo := &cdv1_api.TopologyInfoConfig{
+		Name: &wrapperspb.StringValue{Value: topoName},
+	}
+
+	getAllTopoInfoRequest := &cdv1_api.TopologyInfoConfigStreamRequest{
+		PartialEqFilter: []*cdv1_api.TopologyInfoConfig{topoInfo},
+	}
+
+	log.Printf("[CVaaS-INFO] GetAllTopologyInfoRequest: %v", redact(getAllTopoInfoRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+
+	stream, err := topoInfoClient.GetAll(ctx, getAllTopoInfoRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading grpc stream: %v", err)
+		}
+
+		ent := resp.GetValue()
+		if ent.GetName().GetValue() != topoName || ent.GetTopoType() != topoType {
+			continue
+		}
+		if topoType == cdv1_api.TopologyInfoType_TOPOLOGY_INFO_TYPE_WAN &&
+			ent.GetWanInfo().GetWanName().GetValue() != name {
+			continue
+		}
+		if topoType == cdv1_api.TopologyInfoType_TOPOLOGY_INFO_TYPE_CLOS &&
+			ent.GetClosInfo().GetClosName().GetValue() != name {
+			continue
+		}
+		return ent, nil
+	}
+	return nil, fmt.Errorf("No %s entry found for topology %s", topoType, topoName)
+}
+
 //AddTopology adds Topology resource to Aeris
 func (p *CloudeosProvider) AddTopology(d *schema.ResourceData) error {
 	client, err := p.grpcClient()
@@ -742,7 +1306,6 @@ func (p *CloudeosProvider) AddTopology(d *schema.ResourceData) error {
 		log.Printf("Failed to create new CVaaS Grpc client to execute AddTopology")
 		return err
 	}
-	defer client.Close()
 	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
 	// bgp_asn is not needed when deploy_mode = 'provision'
 	deployMode := d.Get("deploy_mode").(string)
@@ -786,10 +1349,9 @@ func (p *CloudeosProvider) AddTopology(d *schema.ResourceData) error {
 		Value: topoInfo,
 	}
 
-	log.Printf("[CVaaS-INFO] AddTopologyInfoRequest: %v", &addTopoInfoRequest)
+	log.Printf("[CVaaS-INFO] AddTopologyInfoRequest: %v", redact(&addTopoInfoRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	resp, err := topoInfoClient.Set(ctx, &addTopoInfoRequest)
@@ -814,7 +1376,6 @@ func (p *CloudeosProvider) DeleteTopology(d *schema.ResourceData) error {
 		log.Printf("Failed to create new CVaaS Grpc client to execute DeleteTopology")
 		return err
 	}
-	defer client.Close()
 	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
 	topoInfoKey := cdv1_api.TopologyInfoKey{
 		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
@@ -823,10 +1384,9 @@ func (p *CloudeosProvider) DeleteTopology(d *schema.ResourceData) error {
 		Key: &topoInfoKey,
 	}
 
-	log.Printf("[CVaaS-INFO] DeleteTopologyInfoRequest: %v", &delTopoInfoRequest)
+	log.Printf("[CVaaS-INFO] DeleteTopologyInfoRequest: %v", redact(&delTopoInfoRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	resp, err := topoInfoClient.Delete(ctx, &delTopoInfoRequest)
@@ -844,7 +1404,6 @@ func (p *CloudeosProvider) AddClosTopology(d *schema.ResourceData) error {
 		log.Printf("Failed to create new CVaaS Grpc client to execute AddClosTopology")
 		return err
 	}
-	defer client.Close()
 	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
 	fabricName := d.Get("fabric").(string)
 	fabric := cdv1_api.FabricType_FABRIC_TYPE_UNSPECIFIED
@@ -877,9 +1436,8 @@ func (p *CloudeosProvider) AddClosTopology(d *schema.ResourceData) error {
 		Value: topoInfo,
 	}
 
-	log.Printf("[CVaaS-INFO] AddTopologyInfoRequest: %v", &addTopoInfoRequest)
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] AddTopologyInfoRequest: %v", redact(&addTopoInfoRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := topoInfoClient.Set(ctx, &addTopoInfoRequest)
 	if err != nil && resp == nil {
@@ -903,7 +1461,6 @@ func (p *CloudeosProvider) DeleteClosTopology(d *schema.ResourceData) error {
 		log.Printf("Failed to create new CVaaS Grpc client to execute DeleteClosTopology")
 		return err
 	}
-	defer client.Close()
 	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
 
 	topoInfoKey := cdv1_api.TopologyInfoKey{
@@ -912,9 +1469,8 @@ func (p *CloudeosProvider) DeleteClosTopology(d *schema.ResourceData) error {
 	delTopoInfoRequest := cdv1_api.TopologyInfoConfigDeleteRequest{
 		Key: &topoInfoKey,
 	}
-	log.Printf("[CVaaS-INFO] DeleteClosTopologyInfoRequest: %v", &delTopoInfoRequest)
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] DeleteClosTopologyInfoRequest: %v", redact(&delTopoInfoRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := topoInfoClient.Delete(ctx, &delTopoInfoRequest)
 	if err != nil && resp != nil && resp.GetKey().GetId().GetValue() != d.Get("tf_id").(string) {
@@ -931,7 +1487,6 @@ func (p *CloudeosProvider) AddWanTopology(d *schema.ResourceData) error {
 		log.Printf("Failed to create new CVaaS Grpc client to execute AddWanTopology")
 		return err
 	}
-	defer client.Close()
 	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
 	wanInfo := &cdv1_api.WanInfo{
 		WanName:              &wrapperspb.StringValue{Value: d.Get("name").(string)},
@@ -954,10 +1509,9 @@ func (p *CloudeosProvider) AddWanTopology(d *schema.ResourceData) error {
 	addTopoInfoRequest := cdv1_api.TopologyInfoConfigSetRequest{
 		Value: topoInfo,
 	}
-	log.Printf("[CVaaS-INFO] AddWanTopologyInfoRequest: %v", &addTopoInfoRequest)
+	log.Printf("[CVaaS-INFO] AddWanTopologyInfoRequest: %v", redact(&addTopoInfoRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := topoInfoClient.Set(ctx, &addTopoInfoRequest)
 	if err != nil && resp == nil {
@@ -981,7 +1535,6 @@ func (p *CloudeosProvider) DeleteWanTopology(d *schema.ResourceData) error {
 		log.Printf("Failed to create new CVaaS Grpc client to execute DeleteWanTopology")
 		return err
 	}
-	defer client.Close()
 	topoInfoClient := cdv1_api.NewTopologyInfoConfigServiceClient(client)
 	topoInfoKey := cdv1_api.TopologyInfoKey{
 		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
@@ -989,10 +1542,9 @@ func (p *CloudeosProvider) DeleteWanTopology(d *schema.ResourceData) error {
 	delTopoInfoRequest := cdv1_api.TopologyInfoConfigDeleteRequest{
 		Key: &topoInfoKey,
 	}
-	log.Printf("[CVaaS-INFO] DeleteWanTopologyInfoRequest: %v", &delTopoInfoRequest)
+	log.Printf("[CVaaS-INFO] DeleteWanTopologyInfoRequest: %v", redact(&delTopoInfoRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := topoInfoClient.Delete(ctx, &delTopoInfoRequest)
 	if err != nil && resp != nil && resp.GetKey().GetId().GetValue() != d.Get("tf_id").(string) {
@@ -1010,7 +1562,6 @@ func (p *CloudeosProvider) AddSubnet(d *schema.ResourceData) error {
 		return err
 	}
 
-	defer client.Close()
 	subnetClient := cdv1_api.NewSubnetConfigServiceClient(client)
 	cpName := getCloudProviderType(d)
 
@@ -1029,10 +1580,9 @@ func (p *CloudeosProvider) AddSubnet(d *schema.ResourceData) error {
 	addSubnetRequest := cdv1_api.SubnetConfigSetRequest{
 		Value: subnet,
 	}
-	log.Printf("[CVaaS-INFO] AddSubnetRequest: %v", &addSubnetRequest)
+	log.Printf("[CVaaS-INFO] AddSubnetRequest: %v", redact(&addSubnetRequest))
 
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := subnetClient.Set(ctx, &addSubnetRequest)
 	if err != nil && resp == nil {
@@ -1049,6 +1599,70 @@ func (p *CloudeosProvider) AddSubnet(d *schema.ResourceData) error {
 	return nil
 }
 
+//GetSubnetResponse gets the subnet entry for the resource tf_id from Aeris
+func (p *CloudeosProvider) GetSubnetResponse(d *schema.ResourceData) (
+	*cdv1_api.SubnetConfigResponse, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("Failed to create new CVaaS Grpc client to execute GetSubnet")
+		return nil, err
+	}
+
+	subnetClient := cdv1_api.NewSubnetConfigServiceClient(client)
+	subnetKey := cdv1_api.SubnetKey{
+		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
+	}
+	getSubnetRequest := cdv1_api.SubnetConfigRequest{
+		Key: &subnetKey,
+	}
+	log.Printf("[CVaaS-INFO] GetSubnetRequest: %v", redact(&getSubnetRequest))
+
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	resp, err := subnetClient.GetOne(ctx, &getSubnetRequest)
+	log.Printf("[CVaaS-INFO] Received GetSubnetResponse: %v", redact(resp))
+	return resp, err
+}
+
+//GetSubnetBySubnetID gets the subnet entry for the given cloud subnet_id
+func (p *CloudeosProvider) GetSubnetBySubnetID(subnetID string) (*cdv1_api.SubnetConfig, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("Failed to create new CVaaS Grpc client to execute GetSubnetBySubnetID")
+		return nil, err
+	}
+
+	subnetClient := cdv1_api.NewSubnetConfigServiceClient(client)
+	subnet := &cdv1_api.SubnetConfig{
+		SubnetId: &wrapperspb.StringValue{Value: subnetID},
+	}
+	getAllSubnetRequest := &cdv1_api.SubnetConfigStreamRequest{
+		PartialEqFilter: []*cdv1_api.SubnetConfig{subnet},
+	}
+	log.Printf("[CVaaS-INFO] GetAllSubnetRequest: %v", redact(getAllSubnetRequest))
+
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	stream, err := subnetClient.GetAll(ctx, getAllSubnetRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading grpc stream: %v", err)
+		}
+		if resp.GetValue().GetSubnetId().GetValue() == subnetID {
+			return resp.GetValue(), nil
+		}
+	}
+	return nil, fmt.Errorf("No subnet entry found for subnet_id %s", subnetID)
+}
+
 //DeleteSubnet deletes subnet resource from Aeris
 func (p *CloudeosProvider) DeleteSubnet(d *schema.ResourceData) error {
 	client, err := p.grpcClient()
@@ -1057,7 +1671,6 @@ func (p *CloudeosProvider) DeleteSubnet(d *schema.ResourceData) error {
 		return err
 	}
 
-	defer client.Close()
 	subnetClient := cdv1_api.NewSubnetConfigServiceClient(client)
 	subnetKey := cdv1_api.SubnetKey{
 		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
@@ -1065,9 +1678,8 @@ func (p *CloudeosProvider) DeleteSubnet(d *schema.ResourceData) error {
 	delSubnetRequest := cdv1_api.SubnetConfigDeleteRequest{
 		Key: &subnetKey,
 	}
-	log.Printf("[CVaaS-INFO] DeleteSubnetRequest: %v", delSubnetRequest)
-	ctx, cancel := context.WithTimeout(context.Background(),
-		time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] DeleteSubnetRequest: %v", redact(&delSubnetRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := subnetClient.Delete(ctx, &delSubnetRequest)
 	if err != nil && resp != nil && resp.GetKey().GetId().GetValue() != d.Get("tf_id").(string) {
@@ -1086,7 +1698,6 @@ func (p *CloudeosProvider) GetRouterResponse(d *schema.ResourceData) (*cdv1_api.
 		return nil, err
 	}
 
-	defer client.Close()
 	rtrClient := cdv1_api.NewRouterConfigServiceClient(client)
 
 	routerKey := cdv1_api.RouterKey{
@@ -1096,12 +1707,53 @@ func (p *CloudeosProvider) GetRouterResponse(d *schema.ResourceData) (*cdv1_api.
 		Key: &routerKey,
 	}
 
-	log.Printf("[CVaaS-INFO] GetRouterRequest: %v", &getRouterRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] GetRouterRequest: %v", redact(&getRouterRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	return rtrClient.GetOne(ctx, &getRouterRequest)
 }
 
+//GetRouterByName gets the router entry with the given name in the given vpc
+func (p *CloudeosProvider) GetRouterByName(vpcID, name string) (*cdv1_api.RouterConfig, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("GetRouterByName: Failed to create new CVaaS Grpc client, err: %v", err)
+		return nil, err
+	}
+
+	rtrClient := cdv1_api.NewRouterConfigServiceClient(client)
+	rtr := &cdv1_api.RouterConfig{
+		VpcId: &wrapperspb.StringValue{Value: vpcID},
+		Name:  &wrapperspb.StringValue{Value: name},
+	}
+	getAllRouterRequest := &cdv1_api.RouterConfigStreamRequest{
+		PartialEqFilter: []*cdv1_api.RouterConfig{rtr},
+	}
+
+	log.Printf("[CVaaS-INFO] GetAllRouterRequest: %v", redact(getAllRouterRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+	stream, err := rtrClient.GetAll(ctx, getAllRouterRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading grpc stream: %v", err)
+		}
+		ent := resp.GetValue()
+		if ent.GetVpcId().GetValue() == vpcID && ent.GetName().GetValue() == name {
+			return ent, nil
+		}
+	}
+	return nil, fmt.Errorf("No router entry found for router %s in vpc %s", name, vpcID)
+}
+
 //GetRouter gets router details from CloudDeploy
 func (p *CloudeosProvider) GetRouter(d *schema.ResourceData) error {
 	// create new client
@@ -1111,7 +1763,7 @@ func (p *CloudeosProvider) GetRouter(d *schema.ResourceData) error {
 		return err
 	}
 
-	log.Printf("[CVaaS-INFO] Received GetRouterResponse: %v", resp)
+	log.Printf("[CVaaS-INFO] Received GetRouterResponse: %v", redact(resp))
 
 	if resp.GetValue() != nil {
 		if err = parseRtrResponse(resp.GetValue(), d); err != nil {
@@ -1135,7 +1787,7 @@ func (p *CloudeosProvider) GetRouterStatusAndSetBgpAsn(d *schema.ResourceData) e
 		return err
 	}
 
-	log.Printf("[CVaaS-INFO] Received GetRouterResponse: %v", resp)
+	log.Printf("[CVaaS-INFO] Received GetRouterResponse: %v", redact(resp))
 	routerBgpAsn := fmt.Sprint(resp.GetValue().GetBgpAsn().GetValue())
 	if err = d.Set("router_bgp_asn", routerBgpAsn); err != nil {
 		return err
@@ -1151,7 +1803,6 @@ func (p *CloudeosProvider) CheckRouterDeletionStatus(d *schema.ResourceData) err
 		log.Printf("CheckRouterDeletionStatus: Failed to create new CVaaS Grpc client, err: %v", err)
 		return err
 	}
-	defer client.Close()
 	rtrClient := cdv1_api.NewRouterConfigServiceClient(client)
 
 	routerKey := cdv1_api.RouterKey{
@@ -1160,8 +1811,8 @@ func (p *CloudeosProvider) CheckRouterDeletionStatus(d *schema.ResourceData) err
 	getRouterRequest := cdv1_api.RouterConfigRequest{
 		Key: &routerKey,
 	}
-	log.Printf("[CVaaS-INFO] GetRouterRequest: %v", &getRouterRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] GetRouterRequest: %v", redact(&getRouterRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	resp, err := rtrClient.GetOne(ctx, &getRouterRequest)
@@ -1170,7 +1821,7 @@ func (p *CloudeosProvider) CheckRouterDeletionStatus(d *schema.ResourceData) err
 		return err
 	}
 
-	log.Printf("[CVaaS-INFO] Received GetRouter Resp: %v", resp)
+	log.Printf("[CVaaS-INFO] Received GetRouter Resp: %v", redact(resp))
 	// In case of object not existing in aeris, the server returns an empty response
 	// i.e router protobuf with all fields empty, so checking if key is not present
 	// should be sufficient to confirm that router is deleted from aeris
@@ -1185,7 +1836,12 @@ func (p *CloudeosProvider) CheckRouterDeletionStatus(d *schema.ResourceData) err
 
 //AddRouterConfig adds Router resource to Aeris
 func (p *CloudeosProvider) AddRouterConfig(d *schema.ResourceData) error {
-	enrollmentToken, err := p.getDeviceEnrollmentToken()
+	tokenSettings, err := p.getRouterEnrollmentToken(d)
+	if err != nil {
+		log.Printf("Error getting device enrollment token settings, error: %v", err)
+		return err
+	}
+	enrollmentToken, err := p.getDeviceEnrollmentToken(tokenSettings)
 	if err != nil {
 		log.Printf("Error getting device enrollment token, error: %v", err)
 		return err
@@ -1196,7 +1852,6 @@ func (p *CloudeosProvider) AddRouterConfig(d *schema.ResourceData) error {
 		log.Printf("AddRouterConfig: Failed to create new CVaaS Grpc client, err: %v", err)
 		return err
 	}
-	defer client.Close()
 
 	rtrClient := cdv1_api.NewRouterConfigServiceClient(client)
 	routerName, err := getRouterNameFromSchema(d)
@@ -1205,28 +1860,7 @@ func (p *CloudeosProvider) AddRouterConfig(d *schema.ResourceData) error {
 		return err
 	}
 
-	//Adding Intf Private IP, Type and Name to the first message.
-	var intfs []*cdv1_api.NetworkInterface
-	intfNameList := d.Get("intf_name").([]interface{})
-	privateIPList := d.Get("intf_private_ip").([]interface{})
-	intfTypeList := d.Get("intf_type").([]interface{})
-	intfCount := len(intfNameList)
-	for i := 0; i < intfCount; i++ {
-		intf := &cdv1_api.NetworkInterface{
-			Name:          &wrapperspb.StringValue{Value: intfNameList[i].(string)},
-			PrivateIpAddr: &fmp.RepeatedString{Values: []string{privateIPList[i].(string)}},
-		}
-
-		switch {
-		case strings.EqualFold(intfTypeList[i].(string), "public"):
-			intf.IntfType = cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_PUBLIC
-		case strings.EqualFold(intfTypeList[i].(string), "private"):
-			intf.IntfType = cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_PRIVATE
-		case strings.EqualFold(intfTypeList[i].(string), "internal"):
-			intf.IntfType = cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_INTERNAL
-		}
-		intfs = append(intfs, intf)
-	}
+	intfs := getRouterIntfs(d.Get("interface").([]interface{}))
 
 	cpType := getCloudProviderType(d)
 
@@ -1250,8 +1884,8 @@ func (p *CloudeosProvider) AddRouterConfig(d *schema.ResourceData) error {
 	addRouterRequest := cdv1_api.RouterConfigSetRequest{
 		Value: rtr,
 	}
-	log.Printf("[CVaaS-INFO] AddRouterRequest: %v", addRouterRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] AddRouterRequest: %v", redact(&addRouterRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := rtrClient.Set(ctx, &addRouterRequest)
 	if err != nil {
@@ -1259,7 +1893,7 @@ func (p *CloudeosProvider) AddRouterConfig(d *schema.ResourceData) error {
 		return err
 	}
 
-	log.Printf("[CVaaS-INFO] AddRouterResponse: %v", resp)
+	log.Printf("[CVaaS-INFO] AddRouterResponse: %v", redact(resp))
 	if resp.GetValue().GetKey().GetId() != nil {
 		tf_id := resp.GetValue().GetKey().GetId().GetValue()
 		if err = d.Set("tf_id", tf_id); err != nil {
@@ -1284,7 +1918,6 @@ func (p *CloudeosProvider) CheckEdgeRouterPresence(d *schema.ResourceData) error
 		return err
 	}
 
-	defer client.Close()
 	vpcClient := cdv1_api.NewVpcConfigServiceClient(client)
 	cpType := getCloudProviderType(d)
 	// Code for GetAllVpc request
@@ -1298,8 +1931,8 @@ func (p *CloudeosProvider) CheckEdgeRouterPresence(d *schema.ResourceData) error
 		PartialEqFilter: []*cdv1_api.VpcConfig{vpc},
 	}
 
-	log.Printf("[CVaaS-INFO] GetAllRequest: %v", getAllRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] GetAllRequest: %v", redact(getAllRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	stream, err := vpcClient.GetAll(ctx, getAllRequest)
 	if err != nil {
@@ -1332,13 +1965,7 @@ func (p *CloudeosProvider) CheckEdgeRouterPresence(d *schema.ResourceData) error
 	}
 
 	// for list routers
-	clientRtr, err := p.grpcClient()
-	if err != nil {
-		log.Printf("CheckEdgeRouterPresence: Failed to create new CVaaS Grpc client, err: %v", err)
-		return err
-	}
-	defer clientRtr.Close()
-	cRtr := cdv1_api.NewRouterConfigServiceClient(clientRtr)
+	cRtr := cdv1_api.NewRouterConfigServiceClient(client)
 
 	// for each edge VPC check if a leaf router exist
 	for _, edgeVpcID := range edgeVpcIDs {
@@ -1354,9 +1981,9 @@ func (p *CloudeosProvider) CheckEdgeRouterPresence(d *schema.ResourceData) error
 			PartialEqFilter: []*cdv1_api.RouterConfig{rtr},
 		}
 
-		log.Printf("[CVaaS-INFO] GetAllRouterRequest: %v", GetAllRouterRequest)
+		log.Printf("[CVaaS-INFO] GetAllRouterRequest: %v", redact(GetAllRouterRequest))
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+		ctx, cancel := p.requestContext()
 		defer cancel()
 		stream, err := cRtr.GetAll(ctx, GetAllRouterRequest)
 		if err != nil {
@@ -1392,6 +2019,41 @@ func (p *CloudeosProvider) CheckEdgeRouterPresence(d *schema.ResourceData) error
 	return errors.New("no edge router exists")
 }
 
+// getRouterIntfs returns the NetworkInterfaces of the interface blocks of a
+// router resource. intf_id is only set for cloudeos_router_status.
+func getRouterIntfs(intfList []interface{}) []*cdv1_api.NetworkInterface {
+	var intfs []*cdv1_api.NetworkInterface
+	for _, v := range intfList {
+		intfMap := v.(map[string]interface{})
+		var privateIPs []string
+		for _, ip := range intfMap["private_ips"].([]interface{}) {
+			privateIPs = append(privateIPs, ip.(string))
+		}
+		intf := &cdv1_api.NetworkInterface{
+			Name:          &wrapperspb.StringValue{Value: intfMap["name"].(string)},
+			PrivateIpAddr: &fmp.RepeatedString{Values: privateIPs},
+			PublicIpAddr:  &wrapperspb.StringValue{Value: intfMap["public_ip"].(string)},
+			Subnet:        &wrapperspb.StringValue{Value: intfMap["subnet_id"].(string)},
+			SecurityGroup: &wrapperspb.StringValue{Value: intfMap["security_group"].(string)},
+		}
+		if intfID, ok := intfMap["intf_id"]; ok {
+			intf.IntfId = &wrapperspb.StringValue{Value: intfID.(string)}
+		}
+
+		intfType := intfMap["type"].(string)
+		switch {
+		case strings.EqualFold(intfType, "public"):
+			intf.IntfType = cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_PUBLIC
+		case strings.EqualFold(intfType, "private"):
+			intf.IntfType = cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_PRIVATE
+		case strings.EqualFold(intfType, "internal"):
+			intf.IntfType = cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_INTERNAL
+		}
+		intfs = append(intfs, intf)
+	}
+	return intfs
+}
+
 // AddRouter adds Router resource to Aeris
 func (p *CloudeosProvider) AddRouter(d *schema.ResourceData) error {
 	client, err := p.grpcClient()
@@ -1400,7 +2062,6 @@ func (p *CloudeosProvider) AddRouter(d *schema.ResourceData) error {
 		return err
 	}
 
-	defer client.Close()
 	rtrClient := cdv1_api.NewRouterConfigServiceClient(client)
 
 	routerName, err := getRouterNameFromSchema(d)
@@ -1409,41 +2070,9 @@ func (p *CloudeosProvider) AddRouter(d *schema.ResourceData) error {
 		return err
 	}
 
-	var intfs []*cdv1_api.NetworkInterface
-	publicIP, isPublicIP := d.GetOk("public_ip")
-	intfNameList := d.Get("intf_name").([]interface{})
-	intfIDList := d.Get("intf_id").([]interface{})
-	privateIPList := d.Get("intf_private_ip").([]interface{})
-	subnetIDList := d.Get("intf_subnet_id").([]interface{})
-	intfTypeList := d.Get("intf_type").([]interface{})
+	intfs := getRouterIntfs(d.Get("interface").([]interface{}))
 	routeTableList := getAndCreateRouteTableIDs(d)
 
-	intfCount := len(intfNameList)
-	for i := 0; i < intfCount; i++ {
-		intf := &cdv1_api.NetworkInterface{
-			Name:          &wrapperspb.StringValue{Value: intfNameList[i].(string)},
-			IntfId:        &wrapperspb.StringValue{Value: intfIDList[i].(string)},
-			PrivateIpAddr: &fmp.RepeatedString{Values: []string{privateIPList[i].(string)}},
-			Subnet:        &wrapperspb.StringValue{Value: subnetIDList[i].(string)},
-		}
-
-		if i == 0 && isPublicIP {
-			intf.PublicIpAddr = &wrapperspb.StringValue{Value: publicIP.(string)}
-		} else {
-			intf.PublicIpAddr = &wrapperspb.StringValue{Value: ""}
-		}
-		switch {
-		case strings.EqualFold(intfTypeList[i].(string), "public"):
-			intf.IntfType = cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_PUBLIC
-		case strings.EqualFold(intfTypeList[i].(string), "private"):
-			intf.IntfType = cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_PRIVATE
-		case strings.EqualFold(intfTypeList[i].(string), "internal"):
-			intf.IntfType = cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_INTERNAL
-		}
-
-		intfs = append(intfs, intf)
-	}
-
 	cpType := getCloudProviderType(d)
 	rtrKey := &cdv1_api.RouterKey{
 		Id: &wrapperspb.StringValue{Value: d.Get("tf_id").(string)},
@@ -1481,14 +2110,17 @@ func (p *CloudeosProvider) AddRouter(d *schema.ResourceData) error {
 			InstanceType: &wrapperspb.StringValue{Value: d.Get("instance_type").(string)},
 		}
 		rtr.AzRtrDetail = &azrRtrDetail
+	case strings.EqualFold("gcp", cloudProvider):
+		// There's no GCP router detail in the API yet, so the zone and the
+		// instance type of GCP routers aren't stored in CVaaS
 	}
 
 	addRouterRequest := cdv1_api.RouterConfigSetRequest{
 		Value: rtr,
 	}
 
-	log.Printf("[CVaaS-INFO] AddRouterRequest: %v", &addRouterRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] AddRouterRequest: %v", redact(&addRouterRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 	resp, err := rtrClient.Set(ctx, &addRouterRequest)
 	if err != nil {
@@ -1496,7 +2128,7 @@ func (p *CloudeosProvider) AddRouter(d *schema.ResourceData) error {
 		return err
 	}
 
-	log.Printf("[CVaaS-INFO] AddRouterResponse: %v", resp)
+	log.Printf("[CVaaS-INFO] AddRouterResponse: %v", redact(resp))
 	return nil
 }
 
@@ -1507,7 +2139,6 @@ func (p *CloudeosProvider) DeleteRouter(d *schema.ResourceData) error {
 		log.Printf("DeleteRouter: Failed to create new CVaaS Grpc client, err: %v", err)
 		return err
 	}
-	defer client.Close()
 	rtrClient := cdv1_api.NewRouterConfigServiceClient(client)
 
 	routerKey := cdv1_api.RouterKey{
@@ -1516,8 +2147,8 @@ func (p *CloudeosProvider) DeleteRouter(d *schema.ResourceData) error {
 	delRouterRequest := cdv1_api.RouterConfigDeleteRequest{
 		Key: &routerKey,
 	}
-	log.Printf("[CVaaS-INFO] DeleteRouterRequest : %v", delRouterRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(requestTimeout*time.Second))
+	log.Printf("[CVaaS-INFO] DeleteRouterRequest : %v", redact(&delRouterRequest))
+	ctx, cancel := p.requestContext()
 	defer cancel()
 
 	resp, err := rtrClient.Delete(ctx, &delRouterRequest)
@@ -1526,7 +2157,7 @@ func (p *CloudeosProvider) DeleteRouter(d *schema.ResourceData) error {
 		return err
 	}
 
-	log.Printf("[CVaaS-INFO] DeleteRouterResponse: %v", resp)
+	log.Printf("[CVaaS-INFO] DeleteRouterResponse: %v", redact(resp))
 	// check if deleted resource matches with terraform resource
 	if resp.GetKey().GetId().GetValue() != d.Get("tf_id").(string) {
 		return fmt.Errorf("Deleted key %v, tf_id %v", resp.GetKey().GetId().GetValue(),
@@ -1534,3 +2165,38 @@ func (p *CloudeosProvider) DeleteRouter(d *schema.ResourceData) error {
 	}
 	return nil
 }
+
+// GetAllPaths returns the DPS paths matching the fields set in filter
+func (p *CloudeosProvider) GetAllPaths(filter *cdv1_api.Path) ([]*cdv1_api.Path, error) {
+	client, err := p.grpcClient()
+	if err != nil {
+		log.Printf("GetAllPaths: Failed to create new CVaaS Grpc client, err: %v", err)
+		return nil, err
+	}
+	pathClient := cdv1_api.NewPathServiceClient(client)
+
+	getAllPathRequest := &cdv1_api.PathStreamRequest{
+		PartialEqFilter: []*cdv1_api.Path{filter},
+	}
+	log.Printf("[CVaaS-INFO] GetAllPathRequest: %v", redact(getAllPathRequest))
+	ctx, cancel := p.requestContext()
+	defer cancel()
+
+	stream, err := pathClient.GetAll(ctx, getAllPathRequest)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get paths: %v", err)
+	}
+
+	var paths []*cdv1_api.Path
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading grpc stream: %v", err)
+		}
+		paths = append(paths, resp.GetValue())
+	}
+	return paths, nil
+}
@@ -5,9 +5,27 @@
 package cloudeos
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func ctProvider(t *testing.T) *CloudeosProvider {
@@ -28,7 +46,7 @@ func ctProvider(t *testing.T) *CloudeosProvider {
 
 func TestGetAssignment(t *testing.T) {
 	p := ctProvider(t)
-	_, err := p.getAssignment(p.server)
+	_, err := p.getAssignment()
 	if err != nil {
 		t.Fatalf("Failed to get assignment: %s", err)
 	}
@@ -36,8 +54,269 @@ func TestGetAssignment(t *testing.T) {
 
 func TestGetEnrollmentToken(t *testing.T) {
 	p := ctProvider(t)
-	_, err := p.getDeviceEnrollmentToken()
+	_, err := p.getDeviceEnrollmentToken(enrollmentToken{ValidFor: "60s"})
 	if err != nil {
 		t.Fatalf("Failed to get enrollment token: %s", err)
 	}
 }
+
+func TestGrpcClientReuse(t *testing.T) {
+	p := ctProvider(t)
+	defer p.Close()
+	client, err := p.grpcClient()
+	if err != nil {
+		t.Fatalf("Failed to create grpc client: %s", err)
+	}
+	clientAgain, err := p.grpcClient()
+	if err != nil {
+		t.Fatalf("Failed to create grpc client: %s", err)
+	}
+	if client != clientAgain {
+		t.Fatalf("Expected the grpc connection to be reused")
+	}
+}
+
+func TestGrpcTarget(t *testing.T) {
+	for server, want := range map[string]string{
+		"www.arista.io":       "www.arista.io:443",
+		"www.arista.io:8443":  "www.arista.io:8443",
+		"10.1.1.1":            "10.1.1.1:443",
+		"[2001:db8::1]:10443": "[2001:db8::1]:10443",
+	} {
+		p := &CloudeosProvider{server: server}
+		if got := p.grpcTarget(); got != want {
+			t.Errorf("grpcTarget for %s is %s; want %s", server, got, want)
+		}
+	}
+}
+
+// stubTopoServer stands in for the CVaaS TopologyInfoConfigService
+type stubTopoServer struct {
+	cdv1_api.UnimplementedTopologyInfoConfigServiceServer
+}
+
+func (s *stubTopoServer) GetOne(ctx context.Context,
+	req *cdv1_api.TopologyInfoConfigRequest) (*cdv1_api.TopologyInfoConfigResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if auth := md.Get("authorization"); len(auth) != 1 || auth[0] != "Bearer test-token" {
+		return nil, status.Errorf(codes.Unauthenticated, "unexpected token %v", auth)
+	}
+	return &cdv1_api.TopologyInfoConfigResponse{
+		Value: &cdv1_api.TopologyInfoConfig{Key: req.GetKey()},
+	}, nil
+}
+
+func TestPlaintextGrpcClient(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %s", err)
+	}
+	srv := grpc.NewServer()
+	cdv1_api.RegisterTopologyInfoConfigServiceServer(srv, &stubTopoServer{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	p := &CloudeosProvider{
+		srvcAcctToken: "test-token",
+		server:        lis.Addr().String(),
+		insecure:      true,
+	}
+	defer p.Close()
+
+	d := schema.TestResourceDataRaw(t, cloudeosTopology().Schema, map[string]interface{}{})
+	d.Set("tf_id", "ar-topo-test")
+	topoInfo, err := p.GetTopologyInfo(d)
+	if err != nil {
+		t.Fatalf("GetTopologyInfo failed: %s", err)
+	}
+	if got, want := topoInfo.GetKey().GetId().GetValue(), "ar-topo-test"; got != want {
+		t.Fatalf("GetTopologyInfo returned key %s; want %s", got, want)
+	}
+}
+
+func TestOnAssignedHostFailover(t *testing.T) {
+	p := &CloudeosProvider{
+		assignment: &assignment{
+			hosts: []assignedHost{
+				{cluster: "cluster-a", host: "a1"},
+				{cluster: "cluster-a", host: "a2"},
+				{cluster: "cluster-b", host: "b1"},
+			},
+		},
+	}
+	unreachable := map[string]bool{"a1": true, "a2": true}
+
+	var tried []string
+	err := p.onAssignedHost(func(host string) error {
+		tried = append(tried, host)
+		if unreachable[host] {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if want := []string{"a1", "a2", "b1"}; !reflect.DeepEqual(tried, want) {
+		t.Fatalf("Tried hosts %v, want %v", tried, want)
+	}
+
+	// The host which could be reached is tried first from now on
+	tried = nil
+	p.onAssignedHost(func(host string) error {
+		tried = append(tried, host)
+		return nil
+	})
+	if want := []string{"b1"}; !reflect.DeepEqual(tried, want) {
+		t.Fatalf("Tried hosts %v, want %v", tried, want)
+	}
+
+	// A host which replies with an error is not failed over
+	tried = nil
+	err = p.onAssignedHost(func(host string) error {
+		tried = append(tried, host)
+		return &httpError{statusCode: http.StatusForbidden}
+	})
+	if err == nil || len(tried) != 1 {
+		t.Fatalf("Tried hosts %v with error %v, want a single host and an error", tried, err)
+	}
+}
+
+func TestHTTPDo(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		attempts++
+		switch r.URL.Path {
+		case "/flaky":
+			if attempts < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"ok":true}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "not found")
+		}
+	}))
+	defer srv.Close()
+
+	p := &CloudeosProvider{srvcAcctToken: "test-token", retry: defaultRetryPolicy()}
+	p.retry.backoffBase = time.Millisecond
+	p.retry.backoffCap = time.Millisecond
+
+	body, err := p.httpDo("POST", srv.URL+"/flaky", "{}")
+	if err != nil {
+		t.Fatalf("httpDo failed: %s", err)
+	}
+	if string(body) != `{"ok":true}` || attempts != 3 {
+		t.Fatalf("Unexpected body %s after %d attempts", body, attempts)
+	}
+
+	attempts = 0
+	_, err = p.httpDo("POST", srv.URL+"/missing", "{}")
+	var httpErr *httpError
+	if !errors.As(err, &httpErr) || httpErr.statusCode != http.StatusNotFound {
+		t.Fatalf("Expected a 404 httpError, got %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("Non retryable status retried %d times", attempts)
+	}
+}
+
+func TestGetDeviceEnrollmentTokenRequest(t *testing.T) {
+	var got enrollmentTokenMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/resources/admin.Enrollment/AddEnrollmentToken" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"enrollmentToken":{"token":"enroll-token"}}`)
+	}))
+	defer srv.Close()
+
+	p := &CloudeosProvider{
+		srvcAcctToken: "test-token",
+		server:        strings.TrimPrefix(srv.URL, "http://"),
+		insecure:      true,
+	}
+	token, err := p.getDeviceEnrollmentToken(enrollmentToken{
+		ValidFor:        "3600s",
+		ReenrollDevices: []string{"SN-1"},
+	})
+	if err != nil {
+		t.Fatalf("getDeviceEnrollmentToken failed: %s", err)
+	}
+	if token != "enroll-token" {
+		t.Fatalf("Unexpected enrollment token %s", token)
+	}
+	want := enrollmentToken{
+		Groups:          []string{},
+		ReenrollDevices: []string{"SN-1"},
+		ValidFor:        "3600s",
+	}
+	if got.EnrollmentToken == nil || !reflect.DeepEqual(*got.EnrollmentToken, want) {
+		t.Fatalf("Unexpected AddEnrollmentToken request %+v; want %+v",
+			got.EnrollmentToken, want)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	req := &cdv1_api.RouterConfigSetRequest{
+		Value: &cdv1_api.RouterConfig{
+			Name:                  wrapperspb.String("rtr1"),
+			DeviceEnrollmentToken: wrapperspb.String("enrollment-secret"),
+			CvInfo: &cdv1_api.CVInfo{
+				BootstrapCfg: wrapperspb.String("bootstrap-secret"),
+			},
+		},
+	}
+	vpn := &cdv1_api.AWSVpnConfigSetRequest{
+		Value: &cdv1_api.AWSVpnConfig{
+			TunnelInfoList: &cdv1_api.TunnelInfoList{
+				Values: []*cdv1_api.TunnelInfo{{
+					TunnelPresharedKey: wrapperspb.String("psk-secret"),
+					IpsecInfo: &cdv1_api.IpsecInfo{
+						IkePresharedKey: wrapperspb.String("ike-secret"),
+					},
+				}, {
+					TunnelBgpAsn: wrapperspb.String("65000"),
+				}},
+			},
+		},
+	}
+
+	for _, msg := range []proto.Message{req, vpn} {
+		logged := fmt.Sprintf("%v", redact(msg))
+		for _, secret := range []string{"enrollment-secret", "bootstrap-secret",
+			"psk-secret", "ike-secret"} {
+			if strings.Contains(logged, secret) {
+				t.Errorf("%s not redacted in %s", secret, logged)
+			}
+		}
+	}
+	logged := fmt.Sprintf("%v", redact(req))
+	if !strings.Contains(logged, "rtr1") || !strings.Contains(logged, redactedValue) {
+		t.Errorf("Unexpected redacted message %s", logged)
+	}
+	// The message itself is left untouched
+	if got := req.GetValue().GetDeviceEnrollmentToken().GetValue(); got != "enrollment-secret" {
+		t.Errorf("Request modified by redact, token is %q", got)
+	}
+	// An unset sensitive field stays unset
+	if got := redact(vpn).(*cdv1_api.AWSVpnConfigSetRequest).GetValue().GetTunnelInfoList().
+		GetValues()[1].GetTunnelPresharedKey(); got != nil {
+		t.Errorf("Unset preshared key set to %v", got)
+	}
+	var nilResp *cdv1_api.RouterConfigResponse
+	if got := redact(nilResp); got != proto.Message(nilResp) {
+		t.Errorf("redact of nil response returned %v", got)
+	}
+}
@@ -10,8 +10,80 @@ import (
 
 	"github.com/iancoleman/strcase"
 	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// sensitiveFields are the fields of clouddeploy.v1 messages which hold
+// credentials, and are masked by redact
+var sensitiveFields = map[protoreflect.Name]bool{
+	"device_enrollment_token": true,
+	"tunnel_preshared_key":    true,
+	"ike_preshared_key":       true,
+	"bootstrap_cfg":           true,
+}
+
+const redactedValue = "<redacted>"
+
+// redact returns a copy of msg with its sensitive fields masked, to be
+// logged in place of msg. Every request and response logged as [CVaaS-INFO]
+// must go through it.
+func redact(msg proto.Message) proto.Message {
+	if msg == nil || !msg.ProtoReflect().IsValid() {
+		return msg
+	}
+	msg = proto.Clone(msg)
+	redactMessage(msg.ProtoReflect())
+	return msg
+}
+
+func redactMessage(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case sensitiveFields[fd.Name()]:
+			redactField(m, fd)
+		case fd.IsList() && fd.Kind() == protoreflect.MessageKind:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				redactMessage(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Kind() == protoreflect.MessageKind:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				redactMessage(mv.Message())
+				return true
+			})
+		case fd.Kind() == protoreflect.MessageKind:
+			redactMessage(v.Message())
+		}
+		return true
+	})
+}
+
+// redactField masks a sensitive string or wrapped string field, keeping it
+// unset or empty if it was, so that the logs still tell whether it was set
+func redactField(m protoreflect.Message, fd protoreflect.FieldDescriptor) {
+	switch {
+	case fd.IsList() || fd.IsMap():
+		m.Clear(fd)
+	case fd.Kind() == protoreflect.StringKind:
+		if m.Get(fd).String() != "" {
+			m.Set(fd, protoreflect.ValueOfString(redactedValue))
+		}
+	case fd.Kind() == protoreflect.MessageKind:
+		wrapper := m.Get(fd).Message()
+		value := wrapper.Descriptor().Fields().ByName("value")
+		if value == nil || value.Kind() != protoreflect.StringKind {
+			m.Clear(fd)
+			return
+		}
+		if wrapper.Get(value).String() != "" {
+			wrapper.Set(value, protoreflect.ValueOfString(redactedValue))
+		}
+	default:
+		m.Clear(fd)
+	}
+}
+
 /*******************************************************
 * Use getOuterFieldMask() to get fieldMask of the provided protobuf struct.
 The returned fieldMask will have field names of struct set.
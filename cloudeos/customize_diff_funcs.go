@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// immutableAttributes returns a CustomizeDiffFunc which fails the plan when
+// any of the given attributes of an existing resource is changed. When
+// replace_on_change is set, the resource is replaced instead.
+func immutableAttributes(resourceType string, attributes ...string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, m interface{}) error {
+		if d.Id() == "" {
+			return nil
+		}
+		replace := d.Get("replace_on_change").(bool)
+		for _, attribute := range attributes {
+			if !d.HasChange(attribute) {
+				continue
+			}
+			old, new := d.GetChange(attribute)
+			if isZeroValue(old) {
+				continue
+			}
+			if replace {
+				if err := d.ForceNew(attribute); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("Attribute %s of %s %s cannot be changed, old value: %v, "+
+				"new value: %v. Set replace_on_change to true to replace the resource instead",
+				attribute, resourceType, d.Id(), old, new)
+		}
+		return nil
+	}
+}
+
+// isZeroValue returns whether an attribute value read from the state is the
+// zero value of its type or an empty collection, i.e. the attribute wasn't set
+// yet
+func isZeroValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if set, ok := v.(*schema.Set); ok {
+		return set.Len() == 0
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+// replaceOnChangeSchema defines the replace_on_change attribute of the
+// resources using immutableAttributes
+func replaceOnChangeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+		Description: "Replace the resource instead of failing the plan when an " +
+			"attribute which cannot be updated in place is changed",
+	}
+}
+
+// importReplaceOnChange sets replace_on_change of an imported resource to its
+// default, so that the import isn't followed by an update
+func importReplaceOnChange(d *schema.ResourceData) error {
+	return d.Set("replace_on_change", false)
+}
+
+// attributeGetter is implemented by both schema.ResourceData and
+// schema.ResourceDiff, so that the checks below can be unit tested
+type attributeGetter interface {
+	Get(key string) interface{}
+	GetOk(key string) (interface{}, bool)
+}
+
+// unsupportedAttributes returns a CustomizeDiffFunc which fails the plan when
+// any of the given attributes is set on a resource of the given cloud provider
+func unsupportedAttributes(resourceType, cloudProvider string,
+	attributes ...string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, m interface{}) error {
+		return checkUnsupportedAttributes(d, resourceType, cloudProvider, attributes)
+	}
+}
+
+func checkUnsupportedAttributes(d attributeGetter, resourceType, cloudProvider string,
+	attributes []string) error {
+	if !strings.EqualFold(d.Get("cloud_provider").(string), cloudProvider) {
+		return nil
+	}
+	for _, attribute := range attributes {
+		if _, ok := d.GetOk(attribute); ok {
+			return fmt.Errorf("Attribute %s of %s isn't supported with cloud_provider %s",
+				attribute, resourceType, cloudProvider)
+		}
+	}
+	return nil
+}
+
+// gcpZoneInRegion returns a CustomizeDiffFunc which fails the plan when the
+// availability_zone of a GCP resource isn't a zone of its region
+func gcpZoneInRegion(resourceType string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, m interface{}) error {
+		return checkGcpZoneInRegion(d, resourceType)
+	}
+}
+
+func checkGcpZoneInRegion(d attributeGetter, resourceType string) error {
+	if !strings.EqualFold(d.Get("cloud_provider").(string), "gcp") {
+		return nil
+	}
+	if err := validateGcpZone(d.Get("availability_zone").(string),
+		d.Get("region").(string)); err != nil {
+		return fmt.Errorf("Invalid availability_zone of %s: %s", resourceType, err)
+	}
+	return nil
+}
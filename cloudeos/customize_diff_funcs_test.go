@@ -0,0 +1,35 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestIsZeroValue(t *testing.T) {
+	for _, tc := range []struct {
+		value interface{}
+		want  bool
+	}{
+		{nil, true},
+		{"", true},
+		{"topo", false},
+		{0, true},
+		{65000, false},
+		{false, true},
+		{true, false},
+		{[]interface{}{}, true},
+		{[]interface{}{"rtb-1"}, false},
+		{map[string]interface{}{}, true},
+		{schema.NewSet(schema.HashString, nil), true},
+		{schema.NewSet(schema.HashString, []interface{}{"ipsec"}), false},
+	} {
+		if got := isZeroValue(tc.value); got != tc.want {
+			t.Errorf("isZeroValue(%#v) is %v; want %v", tc.value, got, tc.want)
+		}
+	}
+}
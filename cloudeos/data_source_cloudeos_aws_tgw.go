@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"fmt"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+//cloudeosAwsTgw: Define the cloudeos_aws_tgw schema ( input and output variables )
+func cloudeosAwsTgw() *schema.Resource {
+	return &schema.Resource{
+		Read: cloudeosAwsTgwRead,
+
+		Schema: map[string]*schema.Schema{
+			"tgw_id": {
+				Optional:    true,
+				Computed:    true,
+				Type:        schema.TypeString,
+				Description: "Transit Gateway ID",
+			},
+			"topology_name": {
+				Optional:    true,
+				Computed:    true,
+				Type:        schema.TypeString,
+				Description: "Topology name, used to look up the TGW when tgw_id isn't set",
+			},
+			"region": {
+				Optional:    true,
+				Computed:    true,
+				Type:        schema.TypeString,
+				Description: "Region, used to look up the TGW when tgw_id isn't set",
+			},
+			"account_id": {
+				Optional:    true,
+				Computed:    true,
+				Type:        schema.TypeString,
+				Description: "AWS account ID, used to look up the TGW when tgw_id isn't set",
+			},
+			"name": {
+				Computed: true,
+				Type:     schema.TypeString,
+			},
+			"state": {
+				Computed: true,
+				Type:     schema.TypeString,
+			},
+			"attachment_ids": {
+				Computed: true,
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"cnps_to_route_table_id": {
+				Computed:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "TGW route table ID of each segment (CNPS)",
+			},
+			"stats": {
+				Computed: true,
+				Type:     schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: tgwStatsSchema(),
+				},
+			},
+		},
+	}
+}
+
+// tgwStatsSchema is the schema of the TgwStats counters
+func tgwStatsSchema() map[string]*schema.Schema {
+	statsSchema := map[string]*schema.Schema{
+		"stats_time": {
+			Computed: true,
+			Type:     schema.TypeString,
+		},
+	}
+	for _, counter := range []string{"bytes_in", "bytes_out", "packets_in", "packets_out",
+		"packet_drop_count_blackhole", "packet_drop_count_no_route", "bandwidth_in",
+		"bandwidth_out"} {
+		statsSchema[counter] = &schema.Schema{
+			Computed: true,
+			Type:     schema.TypeFloat,
+		}
+	}
+	return statsSchema
+}
+
+func cloudeosAwsTgwRead(d *schema.ResourceData, m interface{}) error {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	var tgw *cdv1_api.AWSTgw
+	if tgwID := d.Get("tgw_id").(string); tgwID != "" {
+		var err error
+		tgw, err = provider.GetAwsTgw(tgwID)
+		if err != nil {
+			return err
+		}
+	} else {
+		filter := &cdv1_api.AWSTgw{}
+		if v, ok := d.GetOk("topology_name"); ok {
+			filter.TopologyName = &wrappers.StringValue{Value: v.(string)}
+		}
+		if v, ok := d.GetOk("region"); ok {
+			filter.Region = &wrappers.StringValue{Value: v.(string)}
+		}
+		if v, ok := d.GetOk("account_id"); ok {
+			filter.AccountId = &wrappers.StringValue{Value: v.(string)}
+		}
+		if filter.TopologyName == nil && filter.Region == nil && filter.AccountId == nil {
+			return fmt.Errorf("One of tgw_id, topology_name, region or account_id must be " +
+				"set for cloudeos_aws_tgw")
+		}
+
+		tgws, err := provider.GetAllAwsTgw(filter)
+		if err != nil {
+			return err
+		}
+		switch len(tgws) {
+		case 0:
+			return fmt.Errorf("No cloudeos_aws_tgw matches the given filters")
+		case 1:
+			tgw = tgws[0]
+		default:
+			return fmt.Errorf("%d cloudeos_aws_tgw match the given filters, use tgw_id or "+
+				"more specific filters", len(tgws))
+		}
+	}
+
+	if err := parseAwsTgw(tgw, d); err != nil {
+		return err
+	}
+	d.SetId("cloudeos-aws-tgw-" + tgw.GetKey().GetTgwId().GetValue())
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+//cloudeosAwsTgwAttachment: Define the cloudeos_aws_tgw_attachment schema ( input and output variables )
+func cloudeosAwsTgwAttachment() *schema.Resource {
+	attachmentSchema := awsTgwAttachmentSchema()
+	attachmentSchema["attachment_id"] = &schema.Schema{
+		Required:    true,
+		Type:        schema.TypeString,
+		Description: "TGW attachment ID",
+	}
+	return &schema.Resource{
+		Read:   cloudeosAwsTgwAttachmentRead,
+		Schema: attachmentSchema,
+	}
+}
+
+//cloudeosAwsTgwAttachments: Define the cloudeos_aws_tgw_attachments schema ( input and output variables )
+func cloudeosAwsTgwAttachments() *schema.Resource {
+	attachmentSchema := awsTgwAttachmentSchema()
+	attachmentSchema["attachment_id"] = &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeString,
+	}
+	return &schema.Resource{
+		Read: cloudeosAwsTgwAttachmentsRead,
+
+		Schema: map[string]*schema.Schema{
+			"tgw_id": {
+				Optional:    true,
+				Type:        schema.TypeString,
+				Description: "Transit Gateway ID of the attachments",
+			},
+			"vpc_id": {
+				Optional:    true,
+				Type:        schema.TypeString,
+				Description: "VPC ID of the attachments",
+			},
+			"topology_name": {
+				Optional:    true,
+				Type:        schema.TypeString,
+				Description: "Topology name of the attachments",
+			},
+			"attachments": {
+				Computed: true,
+				Type:     schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: attachmentSchema,
+				},
+			},
+		},
+	}
+}
+
+// awsTgwAttachmentSchema is the schema of the attributes of a TGW attachment,
+// all computed
+func awsTgwAttachmentSchema() map[string]*schema.Schema {
+	attachmentSchema := map[string]*schema.Schema{
+		"peer_attachment_ids": {
+			Computed: true,
+			Type:     schema.TypeList,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"stats": {
+			Computed: true,
+			Type:     schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: tgwStatsSchema(),
+			},
+		},
+	}
+	for _, attr := range []string{"state", "region", "account_id", "topology_name", "tgw_id",
+		"tgw_name", "route_table_id", "cnps", "resource_id", "vpc_id", "tun1_state",
+		"tun2_state", "tun1_local_ip_outside", "tun1_local_ip_inside",
+		"tun1_remote_ip_outside", "tun1_remote_ip_inside", "tun2_local_ip_outside",
+		"tun2_local_ip_inside", "tun2_remote_ip_outside", "tun2_remote_ip_inside"} {
+		attachmentSchema[attr] = &schema.Schema{
+			Computed: true,
+			Type:     schema.TypeString,
+		}
+	}
+	return attachmentSchema
+}
+
+func cloudeosAwsTgwAttachmentRead(d *schema.ResourceData, m interface{}) error {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	attachment, err := provider.GetAwsTgwAttachment(d.Get("attachment_id").(string))
+	if err != nil {
+		return err
+	}
+	for attr, value := range parseAwsTgwAttachment(attachment) {
+		if err := d.Set(attr, value); err != nil {
+			return fmt.Errorf("Not able to set %s: %v", attr, err)
+		}
+	}
+	d.SetId("cloudeos-aws-tgw-attachment-" + attachment.GetKey().GetAttachmentId().GetValue())
+	return nil
+}
+
+func cloudeosAwsTgwAttachmentsRead(d *schema.ResourceData, m interface{}) error {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	filter := &cdv1_api.AWSTgwAttachment{}
+	var filterIDs []string
+	if v, ok := d.GetOk("tgw_id"); ok {
+		filter.TgwId = &wrappers.StringValue{Value: v.(string)}
+		filterIDs = append(filterIDs, v.(string))
+	}
+	if v, ok := d.GetOk("vpc_id"); ok {
+		filter.VpcId = &wrappers.StringValue{Value: v.(string)}
+		filterIDs = append(filterIDs, v.(string))
+	}
+	if v, ok := d.GetOk("topology_name"); ok {
+		filter.TopologyName = &wrappers.StringValue{Value: v.(string)}
+		filterIDs = append(filterIDs, v.(string))
+	}
+	if len(filterIDs) == 0 {
+		return fmt.Errorf("One of tgw_id, vpc_id or topology_name must be set for " +
+			"cloudeos_aws_tgw_attachments")
+	}
+
+	attachments, err := provider.GetAllAwsTgwAttachment(filter)
+	if err != nil {
+		return err
+	}
+	// Keep the list stable across refreshes
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].GetKey().GetAttachmentId().GetValue() <
+			attachments[j].GetKey().GetAttachmentId().GetValue()
+	})
+
+	var attachmentList []interface{}
+	for _, attachment := range attachments {
+		attachmentList = append(attachmentList, parseAwsTgwAttachment(attachment))
+	}
+	if err := d.Set("attachments", attachmentList); err != nil {
+		return fmt.Errorf("Not able to set attachments: %v", err)
+	}
+	d.SetId("cloudeos-aws-tgw-attachments-" + strings.Join(filterIDs, "-"))
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	fmp "github.com/aristanetworks/cloudvision-go/api/fmp"
+	r "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestDataSourceAwsTgwAttachment(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config:      testDataSourceAwsTgwAttachmentsNoFilterConfig,
+				ExpectError: regexp.MustCompile("One of tgw_id, vpc_id or topology_name"),
+			},
+			{
+				Config: testDataSourceAwsTgwAttachmentConfig,
+				Check:  testDataSourceAwsTgwAttachmentCheck,
+			},
+		},
+	})
+}
+
+var testDataSourceAwsTgwAttachmentsNoFilterConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+data "cloudeos_aws_tgw_attachments" "attachments" {}
+`, os.Getenv("token"))
+
+var testDataSourceAwsTgwAttachmentConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+data "cloudeos_aws_tgw_attachments" "attachments" {
+  tgw_id = "tgw-0a5856fd8cb6fbee6"
+}
+
+data "cloudeos_aws_tgw_attachment" "attachment" {
+  attachment_id = data.cloudeos_aws_tgw_attachments.attachments.attachments[0].attachment_id
+}
+`, os.Getenv("token"))
+
+func testDataSourceAwsTgwAttachmentCheck(s *terraform.State) error {
+	dataSourceState := s.Modules[0].Resources["data.cloudeos_aws_tgw_attachment.attachment"]
+	if dataSourceState == nil {
+		return fmt.Errorf("cloudeos_aws_tgw_attachment data source not found in state")
+	}
+
+	instanceState := dataSourceState.Primary
+	if instanceState == nil {
+		return fmt.Errorf("cloudeos_aws_tgw_attachment has no primary instance")
+	}
+
+	if got, want := instanceState.Attributes["tgw_id"], "tgw-0a5856fd8cb6fbee6"; got != want {
+		return fmt.Errorf("cloudeos_aws_tgw_attachment tgw_id contains %s; want %s", got, want)
+	}
+	return nil
+}
+
+func TestParseAwsTgwAttachment(t *testing.T) {
+	attachment := &cdv1_api.AWSTgwAttachment{
+		Key: &cdv1_api.AWSTgwAttachmentKey{
+			AttachmentId: wrapperspb.String("tgw-attach-1"),
+		},
+		TgwId:             wrapperspb.String("tgw-1"),
+		Tun1State:         wrapperspb.String("UP"),
+		Tun1LocalIpInside: &fmp.IPAddress{Value: "169.254.10.2"},
+		PeerAttachmentIds: &fmp.RepeatedString{Values: []string{"tgw-attach-2"}},
+	}
+
+	d := cloudeosAwsTgwAttachments().TestResourceData()
+	err := d.Set("attachments", []interface{}{parseAwsTgwAttachment(attachment)})
+	if err != nil {
+		t.Fatalf("Failed to set attachments: %s", err)
+	}
+	for attr, want := range map[string]string{
+		"attachments.0.attachment_id":         "tgw-attach-1",
+		"attachments.0.tun1_state":            "UP",
+		"attachments.0.tun1_local_ip_inside":  "169.254.10.2",
+		"attachments.0.peer_attachment_ids.0": "tgw-attach-2",
+		"attachments.0.tun2_state":            "",
+	} {
+		if got := d.Get(attr).(string); got != want {
+			t.Errorf("%s is %q; want %q", attr, got, want)
+		}
+	}
+}
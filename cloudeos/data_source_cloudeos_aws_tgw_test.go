@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	fmp "github.com/aristanetworks/cloudvision-go/api/fmp"
+	r "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestDataSourceAwsTgw(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config:      testDataSourceAwsTgwNoFilterConfig,
+				ExpectError: regexp.MustCompile("One of tgw_id, topology_name, region or account_id"),
+			},
+			{
+				Config: testDataSourceAwsTgwConfig,
+				Check:  testDataSourceAwsTgwCheck,
+			},
+		},
+	})
+}
+
+var testDataSourceAwsTgwNoFilterConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+data "cloudeos_aws_tgw" "tgw" {}
+`, os.Getenv("token"))
+
+var testDataSourceAwsTgwConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+data "cloudeos_aws_tgw" "tgw" {
+  tgw_id = "tgw-0a5856fd8cb6fbee6"
+}
+`, os.Getenv("token"))
+
+func testDataSourceAwsTgwCheck(s *terraform.State) error {
+	dataSourceState := s.Modules[0].Resources["data.cloudeos_aws_tgw.tgw"]
+	if dataSourceState == nil {
+		return fmt.Errorf("cloudeos_aws_tgw data source not found in state")
+	}
+
+	instanceState := dataSourceState.Primary
+	if instanceState == nil {
+		return fmt.Errorf("cloudeos_aws_tgw has no primary instance")
+	}
+
+	if got, want := instanceState.Attributes["tgw_id"], "tgw-0a5856fd8cb6fbee6"; got != want {
+		return fmt.Errorf("cloudeos_aws_tgw tgw_id contains %s; want %s", got, want)
+	}
+	if instanceState.Attributes["state"] == "" {
+		return fmt.Errorf("cloudeos_aws_tgw state not set")
+	}
+	return nil
+}
+
+func TestParseAwsTgw(t *testing.T) {
+	tgw := &cdv1_api.AWSTgw{
+		Key:           &cdv1_api.AWSTgwKey{TgwId: wrapperspb.String("tgw-1")},
+		State:         wrapperspb.String("available"),
+		TopologyName:  wrapperspb.String("topo"),
+		AttachmentIds: &fmp.RepeatedString{Values: []string{"tgw-attach-1", "tgw-attach-2"}},
+		CnpsToRouteTableId: &fmp.MapStringString{
+			Values: map[string]string{"dev": "tgw-rtb-1"},
+		},
+		Stats: &cdv1_api.TgwStats{BytesIn: wrapperspb.Double(42)},
+	}
+
+	d := cloudeosAwsTgw().TestResourceData()
+	if err := parseAwsTgw(tgw, d); err != nil {
+		t.Fatalf("Failed to parse tgw: %s", err)
+	}
+	if got, want := d.Get("attachment_ids.1").(string), "tgw-attach-2"; got != want {
+		t.Errorf("attachment_ids.1 is %s; want %s", got, want)
+	}
+	if got, want := d.Get("cnps_to_route_table_id.dev").(string), "tgw-rtb-1"; got != want {
+		t.Errorf("cnps_to_route_table_id.dev is %s; want %s", got, want)
+	}
+	if got, want := d.Get("stats.0.bytes_in").(float64), 42.0; got != want {
+		t.Errorf("stats.0.bytes_in is %v; want %v", got, want)
+	}
+	if got := d.Get("stats.0.stats_time").(string); got != "" {
+		t.Errorf("stats.0.stats_time is %q; want it unset", got)
+	}
+}
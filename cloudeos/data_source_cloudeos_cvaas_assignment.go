@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+//cloudeosCvaasAssignment: Define the cloudeos_cvaas_assignment schema ( output variables )
+func cloudeosCvaasAssignment() *schema.Resource {
+	return &schema.Resource{
+		Read: cloudeosCvaasAssignmentRead,
+
+		Schema: map[string]*schema.Schema{
+			"cluster": {
+				Computed:    true,
+				Type:        schema.TypeString,
+				Description: "CVaaS cluster the provider is connected to",
+			},
+			"host": {
+				Computed:    true,
+				Type:        schema.TypeString,
+				Description: "CVaaS host the provider is connected to",
+			},
+			"hosts": {
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "All the CVaaS hosts assigned to the service account",
+			},
+		},
+	}
+}
+
+func cloudeosCvaasAssignmentRead(d *schema.ResourceData, m interface{}) error {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+	// Connect first, so that the host reported is one which could be reached
+	if _, err := provider.grpcClient(); err != nil {
+		return err
+	}
+	hosts, current, err := provider.assignedHosts()
+	if err != nil {
+		return err
+	}
+
+	var hostNames []string
+	for _, host := range hosts {
+		hostNames = append(hostNames, host.host)
+	}
+	if err := d.Set("cluster", hosts[current].cluster); err != nil {
+		return err
+	}
+	if err := d.Set("host", hosts[current].host); err != nil {
+		return err
+	}
+	if err := d.Set("hosts", hostNames); err != nil {
+		return err
+	}
+	d.SetId("cloudeos-cvaas-assignment")
+	return nil
+}
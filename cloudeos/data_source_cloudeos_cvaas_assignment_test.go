@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	r "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestDataSourceCvaasAssignment(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config: testDataSourceCvaasAssignmentConfig,
+				Check:  testDataSourceCvaasAssignmentCheck,
+			},
+		},
+	})
+}
+
+var testDataSourceCvaasAssignmentConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+data "cloudeos_cvaas_assignment" "assignment" {}
+`, os.Getenv("token"))
+
+func testDataSourceCvaasAssignmentCheck(s *terraform.State) error {
+	dataSourceState := s.Modules[0].Resources["data.cloudeos_cvaas_assignment.assignment"]
+	if dataSourceState == nil {
+		return fmt.Errorf("cloudeos_cvaas_assignment data source not found in state")
+	}
+
+	instanceState := dataSourceState.Primary
+	if instanceState == nil {
+		return fmt.Errorf("cloudeos_cvaas_assignment has no primary instance")
+	}
+
+	if instanceState.Attributes["host"] == "" {
+		return fmt.Errorf("cloudeos_cvaas_assignment host not set")
+	}
+	if instanceState.Attributes["hosts.#"] == "0" {
+		return fmt.Errorf("cloudeos_cvaas_assignment hosts not set")
+	}
+	return nil
+}
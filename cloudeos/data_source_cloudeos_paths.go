@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+//cloudeosPaths: Define the cloudeos_paths schema ( input and output variables )
+func cloudeosPaths() *schema.Resource {
+	pathSchema := map[string]*schema.Schema{
+		"up": {
+			Computed: true,
+			Type:     schema.TypeBool,
+		},
+	}
+	for _, attr := range []string{"src_vpc_id", "src_vpc_name", "src_region",
+		"src_cloud_provider", "local_router_id", "local_router_name", "local_intf_ip",
+		"dst_vpc_id", "dst_vpc_name", "dst_region", "dst_cloud_provider", "remote_router_id",
+		"remote_router_name", "remote_intf_ip", "underlay_type"} {
+		pathSchema[attr] = &schema.Schema{
+			Computed: true,
+			Type:     schema.TypeString,
+		}
+	}
+	for _, attr := range []string{"latency_ms", "jitter_ms", "pkt_loss_pc", "bw_mbps",
+		"uptime"} {
+		pathSchema[attr] = &schema.Schema{
+			Computed: true,
+			Type:     schema.TypeInt,
+		}
+	}
+
+	return &schema.Resource{
+		Read: cloudeosPathsRead,
+
+		Schema: map[string]*schema.Schema{
+			"topology_name": {
+				Required:    true,
+				Type:        schema.TypeString,
+				Description: "Topology name",
+			},
+			"src_vpc_id": {
+				Optional:    true,
+				Type:        schema.TypeString,
+				Description: "VPC ID of the source of the paths",
+			},
+			"dst_vpc_id": {
+				Optional:    true,
+				Type:        schema.TypeString,
+				Description: "VPC ID of the destination of the paths",
+			},
+			"router_name": {
+				Optional:    true,
+				Type:        schema.TypeString,
+				Description: "Name of the local or remote router of the paths",
+			},
+			"underlay_type": {
+				Optional:     true,
+				Type:         schema.TypeString,
+				Description:  "Underlay connection of the paths: igw/peering/tgw",
+				ValidateFunc: validation.StringInSlice([]string{"igw", "peering", "tgw"}, false),
+			},
+			"paths": {
+				Computed: true,
+				Type:     schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: pathSchema,
+				},
+			},
+		},
+	}
+}
+
+func cloudeosPathsRead(d *schema.ResourceData, m interface{}) error {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	topologyName := d.Get("topology_name").(string)
+	filter := &cdv1_api.Path{
+		TopologyName: &wrappers.StringValue{Value: topologyName},
+	}
+	filterIDs := []string{topologyName}
+	if v, ok := d.GetOk("src_vpc_id"); ok {
+		filter.SrcVpcCloudId = &wrappers.StringValue{Value: v.(string)}
+		filterIDs = append(filterIDs, v.(string))
+	}
+	if v, ok := d.GetOk("dst_vpc_id"); ok {
+		filter.DstVpcCloudId = &wrappers.StringValue{Value: v.(string)}
+		filterIDs = append(filterIDs, v.(string))
+	}
+	if v, ok := d.GetOk("underlay_type"); ok {
+		filter.Key = &cdv1_api.PathKey{UlT: getUnderlayType(v.(string))}
+		filterIDs = append(filterIDs, v.(string))
+	}
+
+	paths, err := provider.GetAllPaths(filter)
+	if err != nil {
+		return err
+	}
+
+	// A router is at either end of a path, which the filter of the request
+	// can't express
+	routerName := d.Get("router_name").(string)
+	var pathList []interface{}
+	for _, path := range paths {
+		if routerName != "" && path.GetLocalRtrName().GetValue() != routerName &&
+			path.GetRemoteRtrName().GetValue() != routerName {
+			continue
+		}
+		pathList = append(pathList, parsePath(path))
+	}
+	if routerName != "" {
+		filterIDs = append(filterIDs, routerName)
+	}
+	// Keep the list stable across refreshes
+	sort.SliceStable(pathList, func(i, j int) bool {
+		return pathSortKey(pathList[i]) < pathSortKey(pathList[j])
+	})
+
+	if err := d.Set("paths", pathList); err != nil {
+		return fmt.Errorf("Not able to set paths: %v", err)
+	}
+	d.SetId("cloudeos-paths-" + strings.Join(filterIDs, "-"))
+	return nil
+}
+
+func pathSortKey(path interface{}) string {
+	attrs := path.(map[string]interface{})
+	return strings.Join([]string{attrs["src_vpc_id"].(string),
+		attrs["local_router_id"].(string), attrs["dst_vpc_id"].(string),
+		attrs["remote_router_id"].(string), attrs["underlay_type"].(string)}, "/")
+}
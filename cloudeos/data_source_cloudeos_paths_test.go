@@ -0,0 +1,121 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	r "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestDataSourcePaths(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config:      testDataSourcePathsInvalidUnderlayConfig,
+				ExpectError: regexp.MustCompile("expected underlay_type to be one of"),
+			},
+			{
+				Config: testDataSourcePathsConfig,
+				Check:  testDataSourcePathsCheck,
+			},
+		},
+	})
+}
+
+var testDataSourcePathsInvalidUnderlayConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+data "cloudeos_paths" "paths" {
+  topology_name = "topo-test2"
+  underlay_type = "vpn"
+}
+`, os.Getenv("token"))
+
+var testDataSourcePathsConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+data "cloudeos_paths" "paths" {
+  topology_name = "topo-test2"
+  underlay_type = "igw"
+}
+`, os.Getenv("token"))
+
+func testDataSourcePathsCheck(s *terraform.State) error {
+	dataSourceState := s.Modules[0].Resources["data.cloudeos_paths.paths"]
+	if dataSourceState == nil {
+		return fmt.Errorf("cloudeos_paths data source not found in state")
+	}
+
+	instanceState := dataSourceState.Primary
+	if instanceState == nil {
+		return fmt.Errorf("cloudeos_paths has no primary instance")
+	}
+
+	if instanceState.Attributes["paths.#"] != "0" {
+		if got, want := instanceState.Attributes["paths.0.underlay_type"], "igw"; got != want {
+			return fmt.Errorf("cloudeos_paths underlay_type contains %s; want %s", got, want)
+		}
+	}
+	return nil
+}
+
+func TestParsePath(t *testing.T) {
+	path := &cdv1_api.Path{
+		Key: &cdv1_api.PathKey{
+			UlT: cdv1_api.UnderlayConnectionType_UNDERLAY_CONNECTION_TYPE_PEERING,
+		},
+		SrcVpcCloudId: wrapperspb.String("vpc-1"),
+		SrcRegion:     wrapperspb.String("us-west-1"),
+		SrcCpT:        cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_AWS,
+		DstCpT:        cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_AZURE,
+		LocalRtrName:  wrapperspb.String("rtr1"),
+		PathChar: &cdv1_api.PathCharacteristics{
+			LatencyMs: wrapperspb.Int64(12),
+			Up:        wrapperspb.Bool(true),
+		},
+	}
+
+	d := cloudeosPaths().TestResourceData()
+	if err := d.Set("paths", []interface{}{parsePath(path)}); err != nil {
+		t.Fatalf("Failed to set paths: %s", err)
+	}
+	for attr, want := range map[string]interface{}{
+		"paths.0.src_vpc_id":         "vpc-1",
+		"paths.0.src_cloud_provider": "aws",
+		"paths.0.dst_cloud_provider": "azure",
+		"paths.0.local_router_name":  "rtr1",
+		"paths.0.underlay_type":      "peering",
+		"paths.0.latency_ms":         12,
+		"paths.0.jitter_ms":          0,
+		"paths.0.up":                 true,
+	} {
+		if got := d.Get(attr); got != want {
+			t.Errorf("%s is %v; want %v", attr, got, want)
+		}
+	}
+	if got := getUnderlayType(getUnderlayTypeName(path.GetKey().GetUlT())); got != path.GetKey().GetUlT() {
+		t.Errorf("Underlay type %v doesn't round trip, got %v", path.GetKey().GetUlT(), got)
+	}
+}
@@ -6,8 +6,13 @@ package cloudeos
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
@@ -16,21 +21,108 @@ func Provider() terraform.ResourceProvider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"cvaas_server": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDEOS_CVAAS_SERVER", nil),
 				Description: "Cvp server hostname / ip address and port for terraform" +
 					" client to authenticate. It must be in format of <hostname>" +
-					" or <hostname>:<port>",
+					" or <hostname>:<port>. Can also be set with the" +
+					" CLOUDEOS_CVAAS_SERVER environment variable",
 			},
 			"service_account_web_token": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Service account web token",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("CLOUDEOS_TOKEN", nil),
+				ConflictsWith: []string{"service_account_token_file"},
+				Description: "Service account web token. Can also be set with the" +
+					" CLOUDEOS_TOKEN environment variable",
+			},
+			"service_account_token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"service_account_web_token"},
+				Description: "Path to a file holding the service account web token. Takes" +
+					" precedence over the CLOUDEOS_TOKEN environment variable",
 			},
 			"cvaas_domain": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "CVaaS Domain name",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDEOS_CVAAS_DOMAIN", nil),
+				Description: "CVaaS Domain name. Can also be set with the" +
+					" CLOUDEOS_CVAAS_DOMAIN environment variable",
+			},
+			"ca_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"insecure"},
+				Description: "Path to a PEM encoded CA bundle used to verify the" +
+					" certificate of cvaas_server, e.g. for an on-prem CloudVision",
+			},
+			"client_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"insecure"},
+				Description:   "Path to a PEM encoded client certificate presented to cvaas_server",
+			},
+			"client_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"insecure"},
+				Description:   "Path to the PEM encoded private key of client_cert_file",
+			},
+			"insecure": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Connect to cvaas_server over plaintext, without TLS. Only" +
+					" meant for development against a local server",
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultRequestTimeout.String(),
+				ValidateFunc: validateDuration,
+				Description: "Time limit for a single request to CVaaS, including its" +
+					" retries, e.g. 3m",
+			},
+			"retry_max_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultRetryMaxAttempts,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Attempts made for a request failing with a retryable code",
+			},
+			"retry_backoff_base": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultRetryBackoffBase.String(),
+				ValidateFunc: validateDuration,
+				Description:  "Backoff before the first retry, doubled for every further retry",
+			},
+			"retry_backoff_cap": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultRetryBackoffCap.String(),
+				ValidateFunc: validateDuration,
+				Description:  "Maximum backoff between retries",
+			},
+			"retry_backoff_jitter": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      defaultRetryBackoffJitter,
+				ValidateFunc: validation.FloatBetween(0, 1),
+				Description:  "Fraction of the backoff randomly added or removed on each retry",
+			},
+			"retryable_codes": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateGrpcCode,
+				},
+				Description: "gRPC codes for which requests are retried, e.g. Unavailable," +
+					" DeadlineExceeded or ResourceExhausted. Defaults to Unavailable",
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
@@ -43,6 +135,14 @@ func Provider() terraform.ResourceProvider {
 			"cloudeos_clos":          cloudeosClos(),
 			"cloudeos_wan":           cloudeosWan(),
 			"cloudeos_aws_vpn":       cloudeosAwsVpn(),
+			"cloudeos_path_sla":      cloudeosPathSla(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"cloudeos_cvaas_assignment":    cloudeosCvaasAssignment(),
+			"cloudeos_aws_tgw":             cloudeosAwsTgw(),
+			"cloudeos_aws_tgw_attachment":  cloudeosAwsTgwAttachment(),
+			"cloudeos_aws_tgw_attachments": cloudeosAwsTgwAttachments(),
+			"cloudeos_paths":               cloudeosPaths(),
 		},
 
 		ConfigureFunc: configureCloudEOSProvider,
@@ -54,8 +154,65 @@ func configureCloudEOSProvider(d *schema.ResourceData) (interface{}, error) {
 	cfg.server = d.Get("cvaas_server").(string)
 	cfg.srvcAcctToken = d.Get("service_account_web_token").(string)
 	cfg.cvaasDomain = d.Get("cvaas_domain").(string)
-	if cfg.server == "" || cfg.srvcAcctToken == "" || cfg.cvaasDomain == "" {
-		return nil, errors.New("Provider not configured correctly")
+	cfg.conn = &grpcConn{}
+	cfg.assignment = &assignment{}
+	if tokenFile := d.Get("service_account_token_file").(string); tokenFile != "" {
+		token, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read service_account_token_file %q: %s",
+				tokenFile, err)
+		}
+		cfg.srvcAcctToken = strings.TrimSpace(string(token))
+		if cfg.srvcAcctToken == "" {
+			return nil, fmt.Errorf("service_account_token_file %q is empty", tokenFile)
+		}
+	}
+	if cfg.server == "" {
+		return nil, errors.New("cvaas_server is not set, set it in the provider block " +
+			"or with the CLOUDEOS_CVAAS_SERVER environment variable")
+	}
+	if cfg.srvcAcctToken == "" {
+		return nil, errors.New("service account token is not set, set " +
+			"service_account_web_token or service_account_token_file in the provider " +
+			"block or the CLOUDEOS_TOKEN environment variable")
+	}
+	if cfg.cvaasDomain == "" {
+		return nil, errors.New("cvaas_domain is not set, set it in the provider block " +
+			"or with the CLOUDEOS_CVAAS_DOMAIN environment variable")
+	}
+
+	cfg.insecure = d.Get("insecure").(bool)
+	caFile := d.Get("ca_file").(string)
+	certFile := d.Get("client_cert_file").(string)
+	keyFile := d.Get("client_key_file").(string)
+	if caFile != "" || certFile != "" || keyFile != "" {
+		tlsConfig, err := newTLSConfig(caFile, certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.tlsConfig = tlsConfig
+	}
+
+	// The durations are already validated by the schema
+	cfg.requestTimeout, _ = time.ParseDuration(d.Get("request_timeout").(string))
+	cfg.retry = defaultRetryPolicy()
+	cfg.retry.maxAttempts = uint(d.Get("retry_max_attempts").(int))
+	cfg.retry.backoffBase, _ = time.ParseDuration(d.Get("retry_backoff_base").(string))
+	cfg.retry.backoffCap, _ = time.ParseDuration(d.Get("retry_backoff_cap").(string))
+	cfg.retry.backoffJitter = d.Get("retry_backoff_jitter").(float64)
+	if cfg.retry.backoffCap < cfg.retry.backoffBase {
+		return nil, fmt.Errorf("retry_backoff_cap %s must not be less than retry_backoff_base %s",
+			cfg.retry.backoffCap, cfg.retry.backoffBase)
+	}
+	if retryableCodes := d.Get("retryable_codes").([]interface{}); len(retryableCodes) > 0 {
+		cfg.retry.codes = nil
+		for _, name := range retryableCodes {
+			code, err := parseGrpcCode(name.(string))
+			if err != nil {
+				return nil, err
+			}
+			cfg.retry.codes = append(cfg.retry.codes, code)
+		}
 	}
 
 	return cfg, nil
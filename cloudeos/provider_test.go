@@ -5,6 +5,10 @@
 package cloudeos
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -32,3 +36,85 @@ func TestProvider_impl(t *testing.T) {
 }
 
 func testAccPreCheck(t *testing.T) {}
+
+// setEnv sets the environment variables for the duration of a test
+func setEnv(t *testing.T, env map[string]string) {
+	for k, v := range env {
+		old, ok := os.LookupEnv(k)
+		os.Setenv(k, v)
+		k := k
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, old)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+}
+
+func testConfigure(t *testing.T, raw map[string]interface{}) (CloudeosProvider, error) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw)
+	cfg, err := configureCloudEOSProvider(d)
+	if err != nil {
+		return CloudeosProvider{}, err
+	}
+	return cfg.(CloudeosProvider), nil
+}
+
+func TestProviderConfigureMissingSettings(t *testing.T) {
+	setEnv(t, map[string]string{
+		"CLOUDEOS_CVAAS_SERVER": "",
+		"CLOUDEOS_TOKEN":        "",
+		"CLOUDEOS_CVAAS_DOMAIN": "",
+	})
+	for missing, raw := range map[string]map[string]interface{}{
+		"cvaas_server": {
+			"service_account_web_token": "token",
+			"cvaas_domain":              "apiserver.arista.io",
+		},
+		"service_account_web_token": {
+			"cvaas_server": "www.arista.io",
+			"cvaas_domain": "apiserver.arista.io",
+		},
+		"cvaas_domain": {
+			"cvaas_server":              "www.arista.io",
+			"service_account_web_token": "token",
+		},
+	} {
+		_, err := testConfigure(t, raw)
+		if err == nil || !strings.Contains(err.Error(), missing) {
+			t.Errorf("Expected an error naming %s, got %v", missing, err)
+		}
+	}
+}
+
+func TestProviderConfigureFromEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		"CLOUDEOS_CVAAS_SERVER": "www.arista.io",
+		"CLOUDEOS_TOKEN":        "env-token",
+		"CLOUDEOS_CVAAS_DOMAIN": "apiserver.arista.io",
+	})
+	cfg, err := testConfigure(t, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if cfg.server != "www.arista.io" || cfg.srvcAcctToken != "env-token" ||
+		cfg.cvaasDomain != "apiserver.arista.io" {
+		t.Fatalf("Provider not configured from the environment: %+v", cfg)
+	}
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	cfg, err = testConfigure(t, map[string]interface{}{
+		"service_account_token_file": tokenFile,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if cfg.srvcAcctToken != "file-token" {
+		t.Fatalf("Token not read from service_account_token_file, got %q", cfg.srvcAcctToken)
+	}
+}
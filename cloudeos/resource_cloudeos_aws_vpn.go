@@ -4,12 +4,20 @@
 package cloudeos
 
 import (
-	//"errors"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"strconv"
 	"strings"
-	//"time"
+	"time"
 
-	//"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 //cloudeosAwsVpnStatus: Define the cloudeosAwsVpnStatus schema ( input and output variables )
@@ -20,6 +28,28 @@ func cloudeosAwsVpn() *schema.Resource {
 		Update: cloudeosAwsVpnUpdate,
 		Delete: cloudeosAwsVpnDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: cloudeosAwsVpnImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    cloudeosAwsVpnV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: cloudeosAwsVpnStateUpgradeV0,
+			},
+			{
+				Version: 1,
+				Type:    cloudeosAwsVpnV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: cloudeosAwsVpnStateUpgradeV1,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"cnps": {
 				Type:        schema.TypeString,
@@ -42,124 +72,409 @@ func cloudeosAwsVpn() *schema.Resource {
 				Description: "VPN Gateway ID",
 			},
 			"vpn_connection_id": {
-				Required:    true,
+				Optional:    true,
 				Type:        schema.TypeString,
 				Description: "Vpn connection ID",
 			},
 			"vpn_tgw_attachment_id": {
 				Optional:    true,
 				Type:        schema.TypeString,
-				Description: "TGW Attachment ID for the VPN connection",
+				Description: "TGW Attachment ID for the VPN connection, the tunnel " +
+					"endpoints and overlay IPs are derived from it when not set",
 			},
 			"cgw_id": {
 				Required:    true,
 				Type:        schema.TypeString,
 				Description: "AWS Customer Gateway ID",
 			},
-			"tunnel1_aws_endpoint_ip": { //tunnel1_address
+			"tunnel": {
 				Required:    true,
-				Type:        schema.TypeString,
-				Description: "Public IP address of the AWS VPN Connection endpoint",
+				Type:        schema.TypeList,
+				MinItems:    1,
+				MaxItems:    2,
+				Description: "Tunnels of the AWS VPN Connection",
+				Elem:        awsVpnTunnelSchema(),
 			},
-			"tunnel1_bgp_asn": { //tunnel1_bgp_asn
+			"vpc_id": {
 				Required:    true,
 				Type:        schema.TypeString,
-				Description: "BGP ASN",
+				Description: "VPC ID for the Router given in \"router_id\"",
 			},
-			"tunnel1_router_overlay_ip": { //tunnel1_cgw_inside_address
-				Required:    true,
+			"tf_id": {
+				Computed:    true,
 				Type:        schema.TypeString,
-				Description: "Tunnel Interface overlay IP address for the router",
+				Description: "Unique resource ID",
 			},
-			"tunnel1_aws_overlay_ip": { //tunnel1_vgw_inside_address
-				Required:    true,
-				Type:        schema.TypeString,
-				Description: "Tunnel IP address of the AWS VPN Connection",
+		},
+		CustomizeDiff: cloudeosAwsVpnCustomizeDiff,
+	}
+}
+
+// Algorithms and Diffie-Hellman groups supported by AWS Site-to-Site VPN
+var (
+	awsVpnEncryptions   = []string{"AES128", "AES256", "AES128-GCM-16", "AES256-GCM-16"}
+	awsVpnIntegrities   = []string{"SHA1", "SHA2-256", "SHA2-384", "SHA2-512"}
+	awsVpnIkeDhGroups   = []int{2, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24}
+	awsVpnIpsecDhGroups = []int{2, 5, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24}
+)
+
+// awsVpnMinDpdTimeout is the minimum dead peer detection timeout of AWS
+// Site-to-Site VPN, in seconds
+const awsVpnMinDpdTimeout = 30
+
+// AWS Site-to-Site VPN tunnel inside addresses are /30 subnets of
+// 169.254.0.0/16, except for the ones reserved by AWS
+var (
+	awsVpnInsideCidr          = mustParseCIDR("169.254.0.0/16")
+	awsVpnReservedInsideCidrs = []*net.IPNet{
+		mustParseCIDR("169.254.0.0/30"),
+		mustParseCIDR("169.254.1.0/30"),
+		mustParseCIDR("169.254.2.0/30"),
+		mustParseCIDR("169.254.3.0/30"),
+		mustParseCIDR("169.254.4.0/30"),
+		mustParseCIDR("169.254.5.0/30"),
+		mustParseCIDR("169.254.169.252/30"),
+	}
+)
+
+// AWS preshared keys are 8 to 64 alphanumeric, period or underscore
+// characters and can't start with a zero
+var awsVpnPresharedKeyRegexp = regexp.MustCompile(`^[A-Za-z1-9._][A-Za-z0-9._]{7,63}$`)
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
+
+//awsVpnTunnelSchema: Define the schema of a tunnel of the AWS VPN Connection
+func awsVpnTunnelSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"aws_endpoint_ip": { //tunnelN_address
+				Optional:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validation.IsIPv4Address,
+				Description:  "Public IP address of the AWS VPN Connection endpoint",
 			},
-			"tunnel1_bgp_holdtime": { //tunnel1_bgp_hold_timer
-				Required:    true,
-				Type:        schema.TypeString,
-				Description: "Hold timer value for BGP",
+			"bgp_asn": { //tunnelN_bgp_asn
+				Required:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateNumericString(1, 4294967295),
+				Description:  "BGP ASN",
 			},
-			"tunnel1_preshared_key": { //tunnel1_preshared_key
-				Required:    true,
-				Type:        schema.TypeString,
-				Description: "Ipsec Preshared key for Tunnel1",
-				Sensitive:   true,
+			"router_overlay_ip": { //tunnelN_cgw_inside_address
+				Optional:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateAwsVpnInsideIP,
+				Description:  "Tunnel Interface overlay IP address for the router",
 			},
-			"tunnel2_aws_endpoint_ip": { //tunnel2_address
-				Required:    true,
-				Type:        schema.TypeString,
-				Description: "Public IP address of the AWS VPN Connection endpoint",
+			"aws_overlay_ip": { //tunnelN_vgw_inside_address
+				Optional:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateAwsVpnInsideIP,
+				Description:  "Tunnel IP address of the AWS VPN Connection",
 			},
-			"tunnel2_bgp_asn": { //tunnel2_bgp_asn
-				Required:    true,
-				Type:        schema.TypeString,
-				Description: "BGP ASN",
+			"bgp_holdtime": { //tunnelN_bgp_holdtime
+				Optional:     true,
+				Type:         schema.TypeString,
+				Default:      "30",
+				ValidateFunc: validateNumericString(3, 65535),
+				Description:  "Hold timer value for BGP",
 			},
-			"tunnel2_router_overlay_ip": { //tunnel2_cgw_inside_address
-				Required:    true,
-				Type:        schema.TypeString,
-				Description: "Tunnel interface IP address for the router",
+			"preshared_key": { //tunnelN_preshared_key
+				Required:  true,
+				Type:      schema.TypeString,
+				Sensitive: true,
+				StateFunc: hashSensitiveValue,
+				ValidateFunc: validation.StringMatch(awsVpnPresharedKeyRegexp,
+					"must be 8 to 64 alphanumeric, period or underscore characters and "+
+						"can't start with 0"),
+				Description: "Ipsec Preshared key, only its hash is kept in the state",
 			},
-			"tunnel2_aws_overlay_ip": { //tunnel2_vgw_inside_address
-				Required:    true,
-				Type:        schema.TypeString,
-				Description: "Tunnel IP address of the AWS VPN Connection",
+			"ipsec": {
+				Optional:    true,
+				Computed:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Description: "IKE and Ipsec parameters, AWS defaults when not set",
+				Elem:        awsVpnIpsecSchema(),
 			},
-			"tunnel2_bgp_holdtime": { //tunnel2_bgp_hold_timer
-				Required:    true,
+			"state": {
+				Computed:    true,
 				Type:        schema.TypeString,
-				Description: "Hold timer value for BGP",
+				Description: "State of the tunnel in the TGW attachment",
 			},
-			"tunnel2_preshared_key": { //tunnel2_preshared_key
-				Required:    true,
-				Type:        schema.TypeString,
-				Description: "Pre shared key for Tunnel1",
-				Sensitive:   true,
+		},
+	}
+}
+
+// validateNumericString returns a SchemaValidateFunc which checks that a
+// string is an integer between min and max
+func validateNumericString(min, max int64) schema.SchemaValidateFunc {
+	return func(val interface{}, key string) (warns []string, errs []error) {
+		v, ok := val.(string)
+		if !ok {
+			errs = append(errs, fmt.Errorf("expected type of %s to be string", key))
+			return
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < min || n > max {
+			errs = append(errs, fmt.Errorf("%q must be an integer between %d and %d, got: %q",
+				key, min, max, v))
+		}
+		return
+	}
+}
+
+func validateAwsVpnInsideIP(val interface{}, key string) (warns []string, errs []error) {
+	v, ok := val.(string)
+	if !ok {
+		errs = append(errs, fmt.Errorf("expected type of %s to be string", key))
+		return
+	}
+	ip := net.ParseIP(v).To4()
+	if ip == nil || !awsVpnInsideCidr.Contains(ip) {
+		errs = append(errs, fmt.Errorf("%q must be an IP address in %s, got: %q", key,
+			awsVpnInsideCidr, v))
+		return
+	}
+	for _, reserved := range awsVpnReservedInsideCidrs {
+		if reserved.Contains(ip) {
+			errs = append(errs, fmt.Errorf("%q must not be in %s which is reserved by AWS, "+
+				"got: %q", key, reserved, v))
+			return
+		}
+	}
+	return
+}
+
+// validateAwsVpnInsideIPs checks that the inside addresses of a tunnel are the
+// two host addresses of the same /30
+func validateAwsVpnInsideIPs(routerIP, awsIP string) error {
+	router := net.ParseIP(routerIP).To4()
+	aws := net.ParseIP(awsIP).To4()
+	if router == nil || aws == nil {
+		// Unknown until applied, or already rejected by the ValidateFunc
+		return nil
+	}
+	mask := net.CIDRMask(30, 32)
+	subnet := router.Mask(mask)
+	if !subnet.Equal(aws.Mask(mask)) {
+		return fmt.Errorf("router_overlay_ip %s and aws_overlay_ip %s aren't in the same /30",
+			routerIP, awsIP)
+	}
+	for _, ip := range []net.IP{router, aws} {
+		if host := ip[3] & 3; host == 0 || host == 3 {
+			return fmt.Errorf("%s is the network or broadcast address of %s/30", ip, subnet)
+		}
+	}
+	if router.Equal(aws) {
+		return fmt.Errorf("router_overlay_ip and aws_overlay_ip are both %s", routerIP)
+	}
+	return nil
+}
+
+//awsVpnIpsecSchema: Define the schema of the IKE and Ipsec parameters of a tunnel
+func awsVpnIpsecSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"ike_integrity": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(awsVpnIntegrities, false),
+				Description:  "IKE (phase 1) integrity algorithm",
 			},
-			"vpc_id": {
-				Required:    true,
-				Type:        schema.TypeString,
-				Description: "VPC ID for the Router given in \"router_id\"",
+			"ike_encryption": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(awsVpnEncryptions, false),
+				Description:  "IKE (phase 1) encryption algorithm",
 			},
-			"tf_id": {
-				Computed:    true,
-				Type:        schema.TypeString,
-				Description: "Unique resource ID",
+			"ike_lifetime": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntBetween(900, 28800),
+				Description:  "IKE (phase 1) lifetime, in seconds",
+			},
+			"ike_pfs": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntInSlice(awsVpnIkeDhGroups),
+				Description:  "IKE (phase 1) Diffie-Hellman group",
+			},
+			"ipsec_integrity": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(awsVpnIntegrities, false),
+				Description:  "Ipsec (phase 2) integrity algorithm",
+			},
+			"ipsec_encryption": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(awsVpnEncryptions, false),
+				Description:  "Ipsec (phase 2) encryption algorithm",
+			},
+			"ipsec_pfs": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntInSlice(awsVpnIpsecDhGroups),
+				Description:  "Ipsec (phase 2) perfect forward secrecy Diffie-Hellman group",
+			},
+			"ipsec_mode": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice([]string{"tunnel"}, false),
+				Description:  "Ipsec mode, AWS only supports tunnel",
+			},
+			"dpd_interval": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Dead peer detection interval, in seconds",
+			},
+			"dpd_retries": {
+				Optional:     true,
+				Computed:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Dead peer detection retries before the peer is declared dead",
 			},
 		},
 	}
 }
-func cloudeosAwsVpnRead(d *schema.ResourceData, m interface{}) error {
+
+func cloudeosAwsVpnCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	return validateAwsVpnTunnels(d.Get("tunnel").([]interface{}))
+}
+
+// validateAwsVpnTunnels checks the attributes of the tunnels which depend on
+// each other
+func validateAwsVpnTunnels(tunnels []interface{}) error {
+	for i, t := range tunnels {
+		tunnel, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		routerIP, _ := tunnel["router_overlay_ip"].(string)
+		awsIP, _ := tunnel["aws_overlay_ip"].(string)
+		if err := validateAwsVpnInsideIPs(routerIP, awsIP); err != nil {
+			return fmt.Errorf("Invalid tunnel %d: %s", i+1, err)
+		}
+		ipsec, _ := tunnel["ipsec"].([]interface{})
+		for _, v := range ipsec {
+			if err := validateAwsVpnIpsec(v); err != nil {
+				return fmt.Errorf("Invalid ipsec of tunnel %d: %s", i+1, err)
+			}
+		}
+	}
 	return nil
 }
 
-func cloudeosAwsVpnUpdate(d *schema.ResourceData, m interface{}) error {
+// validateAwsVpnIpsec checks the IKE and Ipsec parameters of a tunnel which
+// depend on each other
+func validateAwsVpnIpsec(v interface{}) error {
+	ipsec, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	interval, _ := ipsec["dpd_interval"].(int)
+	retries, _ := ipsec["dpd_retries"].(int)
+	if interval != 0 && retries != 0 && interval*retries < awsVpnMinDpdTimeout {
+		return fmt.Errorf("dead peer detection timeout of %ds (dpd_interval * dpd_retries) "+
+			"is below the AWS minimum of %ds", interval*retries, awsVpnMinDpdTimeout)
+	}
+	return nil
+}
+
+func cloudeosAwsVpnRead(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
-	err := provider.AddAwsVpnConfig(d)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+	resp, err := provider.GetAwsVpnConfigResponse(d)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	ent := resp.GetValue()
+	if removeIfGone(d, ent.GetKey().GetTfId().GetValue() != "", d.Id()) {
+		return nil
+	}
+	return parseAwsVpnResponse(ent, getAwsVpnAttachment(provider, ent), d)
+}
+
+// getAwsVpnAttachment returns the TGW attachment of the VPN connection, or nil
+// when the VPN connection has none or it isn't known to CVaaS yet
+func getAwsVpnAttachment(provider CloudeosProvider,
+	ent *cdv1_api.AWSVpnConfig) *cdv1_api.AWSTgwAttachment {
+	attachmentID := ent.GetVpnTgwAttachmentId().GetValue()
+	if attachmentID == "" {
+		return nil
+	}
+	att, err := provider.GetAwsTgwAttachment(attachmentID)
+	if err != nil {
+		log.Printf("[WARN] State of the tunnels of %s unknown: %v",
+			ent.GetVpnConnectionId().GetValue(), err)
+		return nil
+	}
+	return att
+}
+
+func cloudeosAwsVpnUpdate(d *schema.ResourceData, m interface{}) error {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
+	vpn, err := resolveAwsVpnConnection(provider, d)
+	if err != nil {
+		return err
+	}
+	return provider.AddAwsVpnConfig(d, vpn)
 }
 
 func cloudeosAwsVpnDelete(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutDelete))
 
 	err := provider.DeleteAwsVpnConfig(d)
 	if err != nil {
 		return err
 	}
+
+	uuid := "cloudeos-aws-vpn" + strings.TrimPrefix(d.Get("tf_id").(string), AwsVpnPrefix)
+	// wait for aws vpn deletion
+	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		if err := provider.CheckAwsVpnDeletionStatus(d); err != nil {
+			return resource.RetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.New("Failed to destroy " + uuid + " error: " + err.Error())
+	}
+
+	log.Print("Successfully deleted " + uuid)
 	d.SetId("")
 	return nil
 }
 
 func cloudeosAwsVpnCreate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
 
-	err := provider.AddAwsVpnConfig(d)
+	vpn, err := resolveAwsVpnConnection(provider, d)
+	if err != nil {
+		return err
+	}
+	err = provider.AddAwsVpnConfig(d, vpn)
 	if err != nil {
 		return err
 	}
@@ -167,3 +482,258 @@ func cloudeosAwsVpnCreate(d *schema.ResourceData, m interface{}) error {
 	d.SetId(uuid)
 	return nil
 }
+
+// awsVpnDerivedAttrs are the tunnel attributes which are derived from the TGW
+// attachment when they aren't set
+var awsVpnDerivedAttrs = []string{"aws_endpoint_ip", "router_overlay_ip", "aws_overlay_ip"}
+
+// awsVpnAttachmentTunnels returns the tunnel attributes known to CVaaS through
+// the TGW attachment. The local side of the tunnels is the CloudEOS router and
+// the remote side is AWS.
+func awsVpnAttachmentTunnels(att *cdv1_api.AWSTgwAttachment) []map[string]string {
+	return []map[string]string{
+		{
+			"aws_endpoint_ip":   att.GetTun1RemoteIpOutside().GetValue(),
+			"router_overlay_ip": att.GetTun1LocalIpInside().GetValue(),
+			"aws_overlay_ip":    att.GetTun1RemoteIpInside().GetValue(),
+		},
+		{
+			"aws_endpoint_ip":   att.GetTun2RemoteIpOutside().GetValue(),
+			"router_overlay_ip": att.GetTun2LocalIpInside().GetValue(),
+			"aws_overlay_ip":    att.GetTun2RemoteIpInside().GetValue(),
+		},
+	}
+}
+
+// mergeDerivedValue returns the value of an attribute which can be derived
+// from the TGW attachment. The attribute is derived when it isn't set in the
+// configuration, and a value which is set must match the attachment.
+func mergeDerivedValue(attr, configured, derived string) (string, error) {
+	switch {
+	case derived == "" || configured == derived:
+		return configured, nil
+	case configured == "":
+		return derived, nil
+	}
+	return "", fmt.Errorf("%s %s disagrees with %s of the TGW attachment", attr, configured,
+		derived)
+}
+
+// mergeAwsVpnTunnels fills in the tunnel attributes derived from the TGW
+// attachment
+func mergeAwsVpnTunnels(tunnels []interface{}, derived []map[string]string) ([]interface{},
+	error) {
+	merged := make([]interface{}, len(tunnels))
+	for i, t := range tunnels {
+		tunnel := map[string]interface{}{}
+		for attr, value := range t.(map[string]interface{}) {
+			tunnel[attr] = value
+		}
+		for _, attr := range awsVpnDerivedAttrs {
+			var derivedValue string
+			if i < len(derived) {
+				derivedValue = derived[i][attr]
+			}
+			configured, _ := tunnel[attr].(string)
+			value, err := mergeDerivedValue(attr, configured, derivedValue)
+			if err != nil {
+				return nil, fmt.Errorf("Tunnel %d: %s", i+1, err)
+			}
+			if value == "" {
+				return nil, fmt.Errorf("%s of tunnel %d isn't set and isn't known from "+
+					"vpn_tgw_attachment_id", attr, i+1)
+			}
+			tunnel[attr] = value
+		}
+		merged[i] = tunnel
+	}
+	if err := validateAwsVpnTunnels(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// awsVpnConnection is the VPN connection sent to CVaaS. Its attributes which
+// are derived from the TGW attachment aren't kept in the state, so that the
+// configuration alone tells whether they are set.
+type awsVpnConnection struct {
+	vpnConnectionID string
+	tgwID           string
+	tunnels         []interface{}
+}
+
+// resolveAwsVpnConnection returns the VPN connection to send to CVaaS, with
+// vpn_connection_id, tgw_id and the tunnel attributes which aren't set derived
+// from vpn_tgw_attachment_id
+func resolveAwsVpnConnection(provider CloudeosProvider, d *schema.ResourceData) (
+	awsVpnConnection, error) {
+	vpn := awsVpnConnection{
+		vpnConnectionID: d.Get("vpn_connection_id").(string),
+		tgwID:           d.Get("tgw_id").(string),
+	}
+	var derived []map[string]string
+	if attachmentID := d.Get("vpn_tgw_attachment_id").(string); attachmentID != "" {
+		att, err := provider.GetAwsTgwAttachment(attachmentID)
+		if err != nil {
+			return vpn, err
+		}
+		derived = awsVpnAttachmentTunnels(att)
+		if vpn.vpnConnectionID, err = mergeDerivedValue("vpn_connection_id",
+			vpn.vpnConnectionID, att.GetResourceId().GetValue()); err != nil {
+			return vpn, err
+		}
+		if vpn.tgwID, err = mergeDerivedValue("tgw_id", vpn.tgwID,
+			att.GetTgwId().GetValue()); err != nil {
+			return vpn, err
+		}
+	}
+	if vpn.vpnConnectionID == "" {
+		return vpn, fmt.Errorf("vpn_connection_id isn't set and isn't known from " +
+			"vpn_tgw_attachment_id")
+	}
+
+	tunnels, err := mergeAwsVpnTunnels(d.Get("tunnel").([]interface{}), derived)
+	if err != nil {
+		return vpn, err
+	}
+	vpn.tunnels = tunnels
+	if d.IsNewResource() {
+		return vpn, nil
+	}
+
+	// Only the hash of the preshared keys which didn't change is known, send
+	// the keys stored in CVaaS instead
+	resp, err := provider.GetAwsVpnConfigResponse(d)
+	if err != nil {
+		return vpn, err
+	}
+	err = restoreAwsVpnPresharedKeys(tunnels, resp.GetValue().GetTunnelInfoList().GetValues(),
+		func(i int) bool {
+			return d.HasChange(fmt.Sprintf("tunnel.%d.preshared_key", i))
+		})
+	return vpn, err
+}
+
+// restoreAwsVpnPresharedKeys replaces the preshared keys of the tunnels which
+// didn't change, which are hashes read from the state, by the keys in CVaaS
+func restoreAwsVpnPresharedKeys(tunnels []interface{}, cvaasTunnels []*cdv1_api.TunnelInfo,
+	changed func(i int) bool) error {
+	for i, t := range tunnels {
+		if changed(i) {
+			continue
+		}
+		tunnel := t.(map[string]interface{})
+		hash := tunnel["preshared_key"].(string)
+		if i < len(cvaasTunnels) {
+			key := cvaasTunnels[i].GetTunnelPresharedKey().GetValue()
+			if hashSensitiveValue(key) == hash {
+				tunnel["preshared_key"] = key
+				continue
+			}
+		}
+		return fmt.Errorf("preshared_key of tunnel %d isn't known to CVaaS, only its hash is "+
+			"in the state. Change it to set it again", i+1)
+	}
+	return nil
+}
+
+func cloudeosAwsVpnImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData,
+	error) {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	// The import ID is either the tf_id or the vpn_connection_id
+	tfID := d.Id()
+	if !strings.HasPrefix(tfID, AwsVpnPrefix) {
+		awsVpn, err := provider.GetAwsVpnConfigByConnectionID(tfID)
+		if err != nil {
+			return nil, err
+		}
+		tfID = awsVpn.GetKey().GetTfId().GetValue()
+	}
+	if err := d.Set("tf_id", tfID); err != nil {
+		return nil, err
+	}
+
+	resp, err := provider.GetAwsVpnConfigResponse(d)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetValue().GetKey().GetTfId().GetValue() == "" {
+		return nil, fmt.Errorf("cloudeos_aws_vpn %s not found in CVaaS", d.Id())
+	}
+	if err := parseAwsVpnResponse(resp.GetValue(),
+		getAwsVpnAttachment(provider, resp.GetValue()), d); err != nil {
+		return nil, err
+	}
+
+	d.SetId("cloudeos-aws-vpn" + strings.TrimPrefix(tfID, AwsVpnPrefix))
+	return []*schema.ResourceData{d}, nil
+}
+
+// cloudeosAwsVpnV0 is the schema of cloudeos_aws_vpn before the preshared keys
+// were hashed in the state
+func cloudeosAwsVpnV0() *schema.Resource {
+	attrs := []string{"cnps", "tgw_id", "router_id", "vpn_gateway_id",
+		"vpn_connection_id", "vpn_tgw_attachment_id", "cgw_id", "vpc_id", "tf_id"}
+	for _, tunnel := range []string{"tunnel1_", "tunnel2_"} {
+		for _, attr := range []string{"aws_endpoint_ip", "bgp_asn", "router_overlay_ip",
+			"aws_overlay_ip", "bgp_holdtime", "preshared_key"} {
+			attrs = append(attrs, tunnel+attr)
+		}
+	}
+
+	resourceSchema := map[string]*schema.Schema{}
+	for _, attr := range attrs {
+		resourceSchema[attr] = &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		}
+	}
+	return &schema.Resource{Schema: resourceSchema}
+}
+
+// cloudeosAwsVpnV1 is the schema of cloudeos_aws_vpn before the tunnel
+// attributes were moved to tunnel blocks
+func cloudeosAwsVpnV1() *schema.Resource {
+	v1 := cloudeosAwsVpnV0()
+	for _, attr := range []string{"tunnel1_ipsec", "tunnel2_ipsec"} {
+		v1.Schema[attr] = &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem:     awsVpnIpsecSchema(),
+		}
+	}
+	return v1
+}
+
+// cloudeosAwsVpnStateUpgradeV0 replaces the cleartext preshared keys in the
+// state by their hash
+func cloudeosAwsVpnStateUpgradeV0(rawState map[string]interface{},
+	meta interface{}) (map[string]interface{}, error) {
+	for _, attr := range []string{"tunnel1_preshared_key", "tunnel2_preshared_key"} {
+		if key, ok := rawState[attr].(string); ok {
+			rawState[attr] = hashSensitiveValue(key)
+		}
+	}
+	return rawState, nil
+}
+
+// cloudeosAwsVpnStateUpgradeV1 moves the tunnel1_* and tunnel2_* attributes to
+// two tunnel blocks
+func cloudeosAwsVpnStateUpgradeV1(rawState map[string]interface{},
+	meta interface{}) (map[string]interface{}, error) {
+	var tunnels []interface{}
+	for _, prefix := range []string{"tunnel1_", "tunnel2_"} {
+		tunnel := map[string]interface{}{}
+		for _, attr := range []string{"aws_endpoint_ip", "bgp_asn", "router_overlay_ip",
+			"aws_overlay_ip", "bgp_holdtime", "preshared_key", "ipsec"} {
+			tunnel[attr] = rawState[prefix+attr]
+			delete(rawState, prefix+attr)
+		}
+		tunnels = append(tunnels, tunnel)
+	}
+	rawState["tunnel"] = tunnels
+	return rawState, nil
+}
@@ -7,10 +7,16 @@ package cloudeos
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	fmp "github.com/aristanetworks/cloudvision-go/api/fmp"
 	r "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestResourceAwsVpn(t *testing.T) {
@@ -41,18 +47,22 @@ resource "cloudeos_aws_vpn" "vpn_config" {
        router_id                = "rtr1"
        tgw_id                   = "tgw-0a5856fd8cb6fbee6"
 	   vpn_tgw_attachment_id    = "tgw-attach-a1234576"
-       tunnel1_aws_overlay_ip    = "169.254.244.201"
-       tunnel1_bgp_asn          = "64512"
-       tunnel1_bgp_holdtime     = "30"
-       tunnel1_aws_endpoint_ip      = "3.230.55.101"
-       tunnel1_router_overlay_ip = "169.254.244.202"
-	   tunnel1_preshared_key    = "key1"
-       tunnel2_bgp_asn          = "64512"
-       tunnel2_bgp_holdtime     = "30"
-       tunnel2_aws_endpoint_ip      = "34.224.224.37"
-       tunnel2_router_overlay_ip = "169.254.36.110"
-       tunnel2_aws_overlay_ip    = "169.254.36.11"
-	   tunnel2_preshared_key    = "key1"
+       tunnel {
+         aws_endpoint_ip   = "3.230.55.101"
+         aws_overlay_ip    = "169.254.244.201"
+         router_overlay_ip = "169.254.244.202"
+         bgp_asn           = "64512"
+         bgp_holdtime      = "30"
+         preshared_key     = "presharedkey1"
+       }
+       tunnel {
+         aws_endpoint_ip   = "34.224.224.37"
+         aws_overlay_ip    = "169.254.36.9"
+         router_overlay_ip = "169.254.36.10"
+         bgp_asn           = "64512"
+         bgp_holdtime      = "30"
+         preshared_key     = "presharedkey2"
+       }
        vpc_id                   = "vpc-0d981c28a83c3fe55"
        vpn_connection_id        = "vpn-091b0a507e134a329"
 	   vpn_gateway_id			= ""
@@ -78,9 +88,348 @@ func testResourceInitialAwsVpnCheck(s *terraform.State) error {
 	if got, want := instanceState.Attributes["vpc_id"], "vpc-0d981c28a83c3fe55"; got != want {
 		return fmt.Errorf("cloudeos_router_config vpc_id contains %s; want %s", got, want)
 	}
+
+	if got, want := instanceState.Attributes["tunnel.#"], "2"; got != want {
+		return fmt.Errorf("cloudeos_aws_vpn tunnel.# contains %s; want %s", got, want)
+	}
+
+	if got, want := instanceState.Attributes["tunnel.0.preshared_key"],
+		hashSensitiveValue("presharedkey1"); got != want {
+		return fmt.Errorf("cloudeos_aws_vpn tunnel.0.preshared_key contains %s; want %s", got, want)
+	}
 	return nil
 }
 
+func TestAwsVpnStateUpgradeV0(t *testing.T) {
+	v0 := map[string]interface{}{
+		"cnps":                  "dev",
+		"tunnel1_preshared_key": "key1",
+		"tunnel2_preshared_key": "key2",
+	}
+	want := map[string]interface{}{
+		"cnps":                  "dev",
+		"tunnel1_preshared_key": hashSensitiveValue("key1"),
+		"tunnel2_preshared_key": hashSensitiveValue("key2"),
+	}
+	got, err := cloudeosAwsVpnStateUpgradeV0(v0, nil)
+	if err != nil {
+		t.Fatalf("Failed to upgrade state: %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Upgraded state %v; want %v", got, want)
+	}
+	if got["tunnel1_preshared_key"] == "key1" {
+		t.Errorf("Preshared key not hashed")
+	}
+}
+
+func TestAwsVpnStateUpgradeV1(t *testing.T) {
+	ipsec := []interface{}{map[string]interface{}{"ike_encryption": "AES256"}}
+	v1 := map[string]interface{}{
+		"cnps":                      "dev",
+		"tunnel1_aws_endpoint_ip":   "3.230.55.101",
+		"tunnel1_bgp_asn":           "64512",
+		"tunnel1_router_overlay_ip": "169.254.244.202",
+		"tunnel1_aws_overlay_ip":    "169.254.244.201",
+		"tunnel1_bgp_holdtime":      "30",
+		"tunnel1_preshared_key":     hashSensitiveValue("presharedkey1"),
+		"tunnel1_ipsec":             ipsec,
+		"tunnel2_aws_endpoint_ip":   "34.224.224.37",
+		"tunnel2_bgp_asn":           "64512",
+		"tunnel2_router_overlay_ip": "169.254.36.10",
+		"tunnel2_aws_overlay_ip":    "169.254.36.9",
+		"tunnel2_bgp_holdtime":      "30",
+		"tunnel2_preshared_key":     hashSensitiveValue("presharedkey2"),
+	}
+	want := map[string]interface{}{
+		"cnps": "dev",
+		"tunnel": []interface{}{
+			map[string]interface{}{
+				"aws_endpoint_ip":   "3.230.55.101",
+				"bgp_asn":           "64512",
+				"router_overlay_ip": "169.254.244.202",
+				"aws_overlay_ip":    "169.254.244.201",
+				"bgp_holdtime":      "30",
+				"preshared_key":     hashSensitiveValue("presharedkey1"),
+				"ipsec":             ipsec,
+			},
+			map[string]interface{}{
+				"aws_endpoint_ip":   "34.224.224.37",
+				"bgp_asn":           "64512",
+				"router_overlay_ip": "169.254.36.10",
+				"aws_overlay_ip":    "169.254.36.9",
+				"bgp_holdtime":      "30",
+				"preshared_key":     hashSensitiveValue("presharedkey2"),
+				"ipsec":             nil,
+			},
+		},
+	}
+	got, err := cloudeosAwsVpnStateUpgradeV1(v1, nil)
+	if err != nil {
+		t.Fatalf("Failed to upgrade state: %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Upgraded state %v; want %v", got, want)
+	}
+}
+
+func TestAwsVpnTunnelValidation(t *testing.T) {
+	tunnelSchema := awsVpnTunnelSchema().Schema
+	for _, tc := range []struct {
+		attr  string
+		value string
+		valid bool
+	}{
+		{"aws_endpoint_ip", "3.230.55.101", true},
+		{"aws_endpoint_ip", "3.230.55", false},
+		{"bgp_asn", "64512", true},
+		{"bgp_asn", "4294967295", true},
+		{"bgp_asn", "0", false},
+		{"bgp_asn", "4294967296", false},
+		{"bgp_asn", "as64512", false},
+		{"bgp_holdtime", "30", true},
+		{"bgp_holdtime", "2", false},
+		{"bgp_holdtime", "30s", false},
+		{"router_overlay_ip", "169.254.244.202", true},
+		{"router_overlay_ip", "10.0.0.1", false},
+		{"router_overlay_ip", "169.254.1.2", false},
+		{"router_overlay_ip", "169.254.169.253", false},
+		{"preshared_key", "presharedkey1", true},
+		{"preshared_key", "pre_shared.key", true},
+		{"preshared_key", "short", false},
+		{"preshared_key", "0presharedkey", false},
+		{"preshared_key", "preshared-key", false},
+	} {
+		_, errs := tunnelSchema[tc.attr].ValidateFunc(tc.value, tc.attr)
+		if valid := len(errs) == 0; valid != tc.valid {
+			t.Errorf("%s %q valid: %v; want %v, errors: %v", tc.attr, tc.value, valid,
+				tc.valid, errs)
+		}
+	}
+
+	for _, tc := range []struct {
+		routerIP string
+		awsIP    string
+		valid    bool
+	}{
+		{"169.254.244.202", "169.254.244.201", true},
+		{"169.254.36.10", "169.254.36.9", true},
+		// Not known until applied
+		{"", "169.254.36.9", true},
+		{"169.254.36.110", "169.254.36.11", false},
+		{"169.254.36.8", "169.254.36.9", false},
+		{"169.254.36.9", "169.254.36.11", false},
+		{"169.254.36.9", "169.254.36.9", false},
+	} {
+		tunnels := []interface{}{map[string]interface{}{
+			"router_overlay_ip": tc.routerIP,
+			"aws_overlay_ip":    tc.awsIP,
+		}}
+		err := validateAwsVpnTunnels(tunnels)
+		if valid := err == nil; valid != tc.valid {
+			t.Errorf("Overlay IPs %q and %q valid: %v; want %v, error: %v", tc.routerIP,
+				tc.awsIP, valid, tc.valid, err)
+		}
+	}
+}
+
+func TestMergeAwsVpnTunnels(t *testing.T) {
+	att := &cdv1_api.AWSTgwAttachment{
+		Tun1RemoteIpOutside: &fmp.IPAddress{Value: "3.230.55.101"},
+		Tun1LocalIpInside:   &fmp.IPAddress{Value: "169.254.244.202"},
+		Tun1RemoteIpInside:  &fmp.IPAddress{Value: "169.254.244.201"},
+		Tun2RemoteIpOutside: &fmp.IPAddress{Value: "34.224.224.37"},
+		Tun2LocalIpInside:   &fmp.IPAddress{Value: "169.254.36.10"},
+		Tun2RemoteIpInside:  &fmp.IPAddress{Value: "169.254.36.9"},
+	}
+	derived := awsVpnAttachmentTunnels(att)
+
+	// The tunnel endpoints and overlay IPs are filled in
+	tunnels := []interface{}{
+		map[string]interface{}{"bgp_asn": "64512", "aws_endpoint_ip": ""},
+		map[string]interface{}{"bgp_asn": "64512", "aws_endpoint_ip": "34.224.224.37"},
+	}
+	merged, err := mergeAwsVpnTunnels(tunnels, derived)
+	if err != nil {
+		t.Fatalf("Failed to merge tunnels: %s", err)
+	}
+	for i, want := range []map[string]interface{}{
+		{"bgp_asn": "64512", "aws_endpoint_ip": "3.230.55.101",
+			"router_overlay_ip": "169.254.244.202", "aws_overlay_ip": "169.254.244.201"},
+		{"bgp_asn": "64512", "aws_endpoint_ip": "34.224.224.37",
+			"router_overlay_ip": "169.254.36.10", "aws_overlay_ip": "169.254.36.9"},
+	} {
+		if !reflect.DeepEqual(merged[i], want) {
+			t.Errorf("Tunnel %d is %v; want %v", i+1, merged[i], want)
+		}
+	}
+	if tunnels[0].(map[string]interface{})["aws_endpoint_ip"] != "" {
+		t.Errorf("Configured tunnels modified")
+	}
+
+	// A value which is set and disagrees with the attachment is an error
+	tunnels = []interface{}{
+		map[string]interface{}{"aws_endpoint_ip": "3.230.55.102"},
+	}
+	if _, err := mergeAwsVpnTunnels(tunnels, derived); err == nil {
+		t.Error("Expected an error for an aws_endpoint_ip which disagrees with the attachment")
+	}
+
+	// Without an attachment, the endpoints and overlay IPs must be set
+	if _, err := mergeAwsVpnTunnels(tunnels, nil); err == nil {
+		t.Error("Expected an error for tunnels without overlay IPs")
+	}
+}
+
+func TestRestoreAwsVpnPresharedKeys(t *testing.T) {
+	cvaasTunnels := []*cdv1_api.TunnelInfo{
+		{TunnelPresharedKey: wrapperspb.String("presharedkey1")},
+		{TunnelPresharedKey: wrapperspb.String("presharedkey2")},
+	}
+	tunnels := []interface{}{
+		map[string]interface{}{"preshared_key": hashSensitiveValue("presharedkey1")},
+		map[string]interface{}{"preshared_key": "newpresharedkey2"},
+	}
+	err := restoreAwsVpnPresharedKeys(tunnels, cvaasTunnels, func(i int) bool { return i == 1 })
+	if err != nil {
+		t.Fatalf("Failed to restore the preshared keys: %s", err)
+	}
+	for i, want := range []string{"presharedkey1", "newpresharedkey2"} {
+		if got := tunnels[i].(map[string]interface{})["preshared_key"]; got != want {
+			t.Errorf("preshared_key of tunnel %d is %v; want %s", i+1, got, want)
+		}
+	}
+
+	// A hash which doesn't match the key in CVaaS is never sent
+	tunnels = []interface{}{
+		map[string]interface{}{"preshared_key": hashSensitiveValue("otherkey1")},
+	}
+	err = restoreAwsVpnPresharedKeys(tunnels, cvaasTunnels, func(i int) bool { return false })
+	if err == nil {
+		t.Error("Expected an error for a preshared key unknown to CVaaS")
+	}
+}
+
+func TestParseAwsVpnResponse(t *testing.T) {
+	ent := &cdv1_api.AWSVpnConfig{
+		VpnConnectionId:    wrapperspb.String("vpn-1"),
+		VpnTgwAttachmentId: wrapperspb.String("tgw-attach-1"),
+		TunnelInfoList: &cdv1_api.TunnelInfoList{
+			Values: []*cdv1_api.TunnelInfo{
+				{
+					TunnelAwsEndpointIp:   &fmp.IPAddress{Value: "1.1.1.1"},
+					TunnelRouterOverlayIp: &fmp.IPAddress{Value: "169.254.10.2"},
+					TunnelPresharedKey:    wrapperspb.String("presharedkey1"),
+				},
+				{
+					TunnelAwsEndpointIp:   &fmp.IPAddress{Value: "2.2.2.2"},
+					TunnelRouterOverlayIp: &fmp.IPAddress{Value: "169.254.10.6"},
+					TunnelPresharedKey:    wrapperspb.String("presharedkey2"),
+				},
+			},
+		},
+	}
+	att := &cdv1_api.AWSTgwAttachment{
+		Tun1State: wrapperspb.String("UP"),
+		Tun2State: wrapperspb.String("DOWN"),
+	}
+
+	d := cloudeosAwsVpn().TestResourceData()
+	if err := parseAwsVpnResponse(ent, att, d); err != nil {
+		t.Fatalf("Failed to parse the aws vpn: %s", err)
+	}
+	if got := d.Get("vpn_connection_id").(string); got != "vpn-1" {
+		t.Errorf("vpn_connection_id is %q; want vpn-1", got)
+	}
+	for i, want := range []map[string]string{
+		{"aws_endpoint_ip": "1.1.1.1", "router_overlay_ip": "169.254.10.2",
+			"preshared_key": hashSensitiveValue("presharedkey1"), "state": "UP"},
+		{"aws_endpoint_ip": "2.2.2.2", "router_overlay_ip": "169.254.10.6",
+			"preshared_key": hashSensitiveValue("presharedkey2"), "state": "DOWN"},
+	} {
+		for attr, value := range want {
+			key := fmt.Sprintf("tunnel.%d.%s", i, attr)
+			if got := d.Get(key).(string); got != value {
+				t.Errorf("%s is %q; want %q", key, got, value)
+			}
+		}
+	}
+
+	// The tunnel state is unknown without the attachment
+	d = cloudeosAwsVpn().TestResourceData()
+	if err := parseAwsVpnResponse(ent, nil, d); err != nil {
+		t.Fatalf("Failed to parse the aws vpn: %s", err)
+	}
+	if got := d.Get("tunnel.0.state").(string); got != "" {
+		t.Errorf("tunnel.0.state is %q; want empty", got)
+	}
+}
+
+func TestAwsVpnIpsec(t *testing.T) {
+	ipsec := map[string]interface{}{
+		"ike_integrity":    "SHA2-256",
+		"ike_encryption":   "AES256-GCM-16",
+		"ike_lifetime":     28800,
+		"ike_pfs":          20,
+		"ipsec_integrity":  "SHA2-256",
+		"ipsec_encryption": "AES256-GCM-16",
+		"ipsec_pfs":        20,
+		"ipsec_mode":       "tunnel",
+		"dpd_interval":     10,
+		"dpd_retries":      3,
+	}
+	info := getIpsecInfo([]interface{}{ipsec})
+	if got := info.GetIkeEncryption().GetValue(); got != "AES256-GCM-16" {
+		t.Errorf("ike_encryption is %q; want AES256-GCM-16", got)
+	}
+	if got := info.GetIkePfs().GetValue(); got != "20" {
+		t.Errorf("ike_pfs is %q; want 20", got)
+	}
+	if got := parseIpsecInfo(info); !reflect.DeepEqual(got, []interface{}{ipsec}) {
+		t.Errorf("ipsec block doesn't round trip, got %v; want %v", got, ipsec)
+	}
+	if info := getIpsecInfo(nil); info != nil {
+		t.Errorf("Ipsec Info %v set without an ipsec block", info)
+	}
+	// An Ipsec Info without parameters is read back as no ipsec block
+	for _, info := range []*cdv1_api.IpsecInfo{nil, {}} {
+		if got := parseIpsecInfo(info); got != nil {
+			t.Errorf("ipsec block %v read from Ipsec Info %v; want none", got, info)
+		}
+	}
+
+	// Parameters which aren't set are left to the AWS defaults
+	info = getIpsecInfo([]interface{}{map[string]interface{}{
+		"ike_encryption": "AES256",
+		"ike_lifetime":   0,
+	}})
+	if info.GetIkeLifetime() != nil || info.GetIkeIntegrity() != nil {
+		t.Errorf("Unset parameters sent in %v", info)
+	}
+
+	if err := validateAwsVpnIpsec(ipsec); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	ipsec["dpd_retries"] = 2
+	if err := validateAwsVpnIpsec(ipsec); err == nil {
+		t.Error("Expected an error for a dead peer detection timeout below 30s")
+	}
+
+	ipsecSchema := awsVpnIpsecSchema().Schema
+	for attr, value := range map[string]interface{}{
+		"ike_encryption": "3DES",
+		"ike_integrity":  "MD5",
+		"ike_pfs":        5,
+		"ipsec_pfs":      1,
+		"ike_lifetime":   600,
+		"ipsec_mode":     "transport",
+	} {
+		if _, errs := ipsecSchema[attr].ValidateFunc(value, attr); len(errs) == 0 {
+			t.Errorf("Expected %s %v to be rejected", attr, value)
+		}
+	}
+}
+
 func testResourceAwsVpnDestroy(s *terraform.State) error {
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudeos_aws_vpn" {
@@ -91,3 +440,51 @@ func testResourceAwsVpnDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestAwsVpnExplicitDerivedValue(t *testing.T) {
+	ent := &cdv1_api.AWSVpnConfig{
+		VpnConnectionId:    wrapperspb.String("vpn-1"),
+		VpnTgwAttachmentId: wrapperspb.String("tgw-attach-1"),
+		TunnelInfoList: &cdv1_api.TunnelInfoList{
+			Values: []*cdv1_api.TunnelInfo{
+				{TunnelAwsEndpointIp: &fmp.IPAddress{Value: "3.230.55.101"}},
+			},
+		},
+	}
+	att := &cdv1_api.AWSTgwAttachment{
+		ResourceId:          wrapperspb.String("vpn-1"),
+		Tun1RemoteIpOutside: &fmp.IPAddress{Value: "3.230.55.101"},
+	}
+
+	// The derived values aren't kept in the state
+	d := cloudeosAwsVpn().TestResourceData()
+	if err := parseAwsVpnResponse(ent, att, d); err != nil {
+		t.Fatalf("Failed to parse the aws vpn: %s", err)
+	}
+	for _, key := range []string{"vpn_connection_id", "tunnel.0.aws_endpoint_ip"} {
+		if got := d.Get(key).(string); got != "" {
+			t.Errorf("Derived %s is %q in the state; want empty", key, got)
+		}
+	}
+
+	// A value which is set to the derived one stays set
+	d = schema.TestResourceDataRaw(t, cloudeosAwsVpn().Schema, map[string]interface{}{
+		"vpn_tgw_attachment_id": "tgw-attach-1",
+		"tunnel": []interface{}{
+			map[string]interface{}{"aws_endpoint_ip": "3.230.55.101"},
+		},
+	})
+	if err := parseAwsVpnResponse(ent, att, d); err != nil {
+		t.Fatalf("Failed to parse the aws vpn: %s", err)
+	}
+	configured := d.Get("tunnel.0.aws_endpoint_ip").(string)
+	if configured != "3.230.55.101" {
+		t.Fatalf("aws_endpoint_ip is %q; want 3.230.55.101", configured)
+	}
+	// and must keep matching the attachment
+	att.Tun1RemoteIpOutside = &fmp.IPAddress{Value: "3.230.55.102"}
+	tunnels := []interface{}{map[string]interface{}{"aws_endpoint_ip": configured}}
+	if _, err := mergeAwsVpnTunnels(tunnels, awsVpnAttachmentTunnels(att)); err == nil {
+		t.Error("Expected an error for an aws_endpoint_ip which disagrees with the attachment")
+	}
+}
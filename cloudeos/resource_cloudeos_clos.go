@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -23,22 +24,24 @@ func cloudeosClos() *schema.Resource {
 		Update: cloudeosClosUpdate,
 		Delete: cloudeosClosDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: cloudeosClosImport,
+		},
+
 		Timeouts: &schema.ResourceTimeout{
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:             schema.TypeString,
-				Required:         true,
-				Description:      "Clos topology name",
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Clos topology name",
 			},
 			"topology_name": {
-				Type:             schema.TypeString,
-				Required:         true,
-				Description:      "Base topology name",
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Base topology name",
 			},
 			"fabric": {
 				Type:        schema.TypeString,
@@ -70,22 +73,25 @@ func cloudeosClos() *schema.Resource {
 				Default:  false,
 			},
 			"cv_container_name": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				Default:          "CloudLeaf",
-				Description:      "Container name for leaf",
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "CloudLeaf",
+				Description: "Container name for leaf",
 			},
 			"tf_id": {
 				Computed: true,
 				Type:     schema.TypeString,
 			},
+			"replace_on_change": replaceOnChangeSchema(),
 		},
+		CustomizeDiff: immutableAttributes("cloudeos_clos",
+			"name", "topology_name", "cv_container_name"),
 	}
 }
 
 func cloudeosClosCreate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
 	allowed, err := provider.IsValidTopoAddition(d, "TOPO_INFO_CLOS")
 	if !allowed || err != nil {
 		return err
@@ -102,11 +108,24 @@ func cloudeosClosCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func cloudeosClosRead(d *schema.ResourceData, m interface{}) error {
-	return nil
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+	topoInfo, err := provider.GetTopologyInfo(d)
+	if err != nil {
+		return err
+	}
+
+	uuid := "cloudeos-clos" + strings.TrimPrefix(d.Get("tf_id").(string), ClosPrefix)
+	if removeIfGone(d, topoInfo.GetKey().GetId().GetValue() != "", uuid) {
+		return nil
+	}
+
+	return parseClosResponse(topoInfo, d)
 }
 
 func cloudeosClosUpdate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
 	err := provider.AddClosTopology(d)
 	if err != nil {
 		return err
@@ -119,6 +138,7 @@ func cloudeosClosUpdate(d *schema.ResourceData, m interface{}) error {
 
 func cloudeosClosDelete(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutDelete))
 	err := provider.DeleteClosTopology(d)
 	if err != nil {
 		return err
@@ -140,3 +160,42 @@ func cloudeosClosDelete(d *schema.ResourceData, m interface{}) error {
 	d.SetId("")
 	return nil
 }
+
+func cloudeosClosImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData,
+	error) {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	// The import ID is either the tf_id or <topology_name>/<name>
+	tfID := d.Id()
+	if !strings.HasPrefix(tfID, ClosPrefix) {
+		parts, err := splitImportID(tfID, 2, "<topology_name>/<name>")
+		if err != nil {
+			return nil, err
+		}
+		topoInfo, err := provider.GetTopologyInfoByName(parts[0], parts[1],
+			cdv1_api.TopologyInfoType_TOPOLOGY_INFO_TYPE_CLOS)
+		if err != nil {
+			return nil, err
+		}
+		tfID = topoInfo.GetKey().GetId().GetValue()
+	}
+	if err := d.Set("tf_id", tfID); err != nil {
+		return nil, err
+	}
+	if err := importReplaceOnChange(d); err != nil {
+		return nil, err
+	}
+
+	topoInfo, err := provider.GetTopologyInfo(d)
+	if err != nil {
+		return nil, err
+	}
+	if topoInfo.GetKey().GetId().GetValue() == "" ||
+		topoInfo.GetTopoType() != cdv1_api.TopologyInfoType_TOPOLOGY_INFO_TYPE_CLOS {
+		return nil, fmt.Errorf("cloudeos_clos %s not found in CVaaS", d.Id())
+	}
+
+	d.SetId("cloudeos-clos" + strings.TrimPrefix(tfID, ClosPrefix))
+	return []*schema.ResourceData{d}, nil
+}
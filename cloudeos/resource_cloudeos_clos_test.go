@@ -29,8 +29,18 @@ func TestResourceClos(t *testing.T) {
 				ExpectError: regexp.MustCompile("cloudeos_clos clos-test3 already exists"),
 			},
 			{
-				Config: testResourceUpdatedClosConfig,
-				Check:  testResourceUpdatedClosCheck,
+				Config:      testResourceUpdatedClosConfig,
+				ExpectError: regexp.MustCompile("Attribute name of cloudeos_clos .* cannot be changed"),
+			},
+			{
+				Config: testResourceReplacedClosConfig,
+				Check:  testResourceReplacedClosCheck,
+			},
+			{
+				ResourceName:      "cloudeos_clos.clos",
+				ImportState:       true,
+				ImportStateId:     "topo-test3/clos-test-update3",
+				ImportStateVerify: true,
 			},
 		},
 	})
@@ -143,7 +153,31 @@ resource "cloudeos_clos" "clos" {
 }
 `, os.Getenv("token"))
 
-func testResourceUpdatedClosCheck(s *terraform.State) error {
+var testResourceReplacedClosConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+resource "cloudeos_topology" "topology" {
+   topology_name = "topo-test3"
+   bgp_asn = "65000-65100"
+   vtep_ip_cidr = "1.0.0.0/16"
+   terminattr_ip_cidr = "2.0.0.0/16"
+   dps_controlplane_cidr = "3.0.0.0/16"
+}
+
+resource "cloudeos_clos" "clos" {
+   name = "clos-test-update3"
+   topology_name = cloudeos_topology.topology.topology_name
+   cv_container_name = "CloudLeaf"
+   replace_on_change = true
+}
+`, os.Getenv("token"))
+
+func testResourceReplacedClosCheck(s *terraform.State) error {
 	resourceState := s.Modules[0].Resources["cloudeos_clos.clos"]
 	if resourceState == nil {
 		return fmt.Errorf("cloudeos_clos.clos resource not found in state")
@@ -154,8 +188,8 @@ func testResourceUpdatedClosCheck(s *terraform.State) error {
 		return fmt.Errorf("cloudeos_clos.clos resource has no primary instance")
 	}
 
-	if instanceState.ID != closResourceID {
-		return fmt.Errorf("cloudeos_clos.clos ID has changed %s", instanceState.ID)
+	if instanceState.ID == closResourceID {
+		return fmt.Errorf("cloudeos_clos.clos ID has not changed %s", instanceState.ID)
 	}
 
 	if got, want := instanceState.Attributes["name"], "clos-test-update3"; got != want {
@@ -0,0 +1,271 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+//cloudeosPathSla: Define the cloudeos_path_sla schema ( input and output variables )
+func cloudeosPathSla() *schema.Resource {
+	return &schema.Resource{
+		Create: cloudeosPathSlaCreate,
+		Read:   cloudeosPathSlaRead,
+		Update: cloudeosPathSlaUpdate,
+		Delete: cloudeosPathSlaDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"topology_name": {
+				Required:    true,
+				Type:        schema.TypeString,
+				ForceNew:    true,
+				Description: "Topology name",
+			},
+			"path": {
+				Required:    true,
+				Type:        schema.TypeList,
+				Description: "Source and destination VPCs expected to be connected",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"src_vpc_id": {
+							Required: true,
+							Type:     schema.TypeString,
+						},
+						"dst_vpc_id": {
+							Required: true,
+							Type:     schema.TypeString,
+						},
+					},
+				},
+			},
+			"max_latency_ms": {
+				Optional:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum latency of the paths, in milliseconds",
+			},
+			"max_jitter_ms": {
+				Optional:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum jitter of the paths, in milliseconds",
+			},
+			"max_pkt_loss_pc": {
+				Optional:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntBetween(0, 100),
+				Description:  "Maximum packet loss of the paths, in percent",
+			},
+			"triggers": {
+				Optional:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				ForceNew:    true,
+				Description: "Arbitrary values which check the paths again when changed",
+			},
+		},
+	}
+}
+
+// pathSLA is the expected state of the paths between VPCs
+type pathSLA struct {
+	// pairs of source and destination VPC IDs
+	pairs [][2]string
+	// thresholds, nil when not checked
+	maxLatencyMs *int64
+	maxJitterMs  *int64
+	maxPktLossPc *int64
+}
+
+// pathSLAViolation is a path, or a missing path, which doesn't meet the SLA
+type pathSLAViolation struct {
+	srcVpcID string
+	dstVpcID string
+	path     *cdv1_api.Path
+	reason   string
+}
+
+func getPathSLA(d *schema.ResourceData) pathSLA {
+	var sla pathSLA
+	for _, p := range d.Get("path").([]interface{}) {
+		pair := p.(map[string]interface{})
+		sla.pairs = append(sla.pairs,
+			[2]string{pair["src_vpc_id"].(string), pair["dst_vpc_id"].(string)})
+	}
+	threshold := func(attr string) *int64 {
+		// GetOk can't tell an unset threshold from a threshold of 0
+		if v, ok := d.GetOkExists(attr); ok {
+			value := int64(v.(int))
+			return &value
+		}
+		return nil
+	}
+	sla.maxLatencyMs = threshold("max_latency_ms")
+	sla.maxJitterMs = threshold("max_jitter_ms")
+	sla.maxPktLossPc = threshold("max_pkt_loss_pc")
+	return sla
+}
+
+// check returns the expected paths which are missing, down or above a
+// threshold. Every path between an expected pair of VPCs must meet the SLA.
+func (sla pathSLA) check(paths []*cdv1_api.Path) []pathSLAViolation {
+	var violations []pathSLAViolation
+	for _, pair := range sla.pairs {
+		found := false
+		for _, path := range paths {
+			if path.GetSrcVpcCloudId().GetValue() != pair[0] ||
+				path.GetDstVpcCloudId().GetValue() != pair[1] {
+				continue
+			}
+			found = true
+			if reason := sla.checkPath(path); reason != "" {
+				violations = append(violations, pathSLAViolation{
+					srcVpcID: pair[0],
+					dstVpcID: pair[1],
+					path:     path,
+					reason:   reason,
+				})
+			}
+		}
+		if !found {
+			violations = append(violations, pathSLAViolation{
+				srcVpcID: pair[0],
+				dstVpcID: pair[1],
+				reason:   "no path",
+			})
+		}
+	}
+	return violations
+}
+
+func (sla pathSLA) checkPath(path *cdv1_api.Path) string {
+	pathChar := path.GetPathChar()
+	switch {
+	case !pathChar.GetUp().GetValue():
+		return "down"
+	case sla.maxLatencyMs != nil && pathChar.GetLatencyMs().GetValue() > *sla.maxLatencyMs:
+		return fmt.Sprintf("latency above %dms", *sla.maxLatencyMs)
+	case sla.maxJitterMs != nil && pathChar.GetJitterMs().GetValue() > *sla.maxJitterMs:
+		return fmt.Sprintf("jitter above %dms", *sla.maxJitterMs)
+	case sla.maxPktLossPc != nil && pathChar.GetPktLossPc().GetValue() > *sla.maxPktLossPc:
+		return fmt.Sprintf("packet loss above %d%%", *sla.maxPktLossPc)
+	}
+	return ""
+}
+
+// formatPathSLAViolations returns a table of the violations, for the error
+// returned when the SLA isn't met in time
+func formatPathSLAViolations(violations []pathSLAViolation) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SRC VPC\tDST VPC\tLOCAL ROUTER\tREMOTE ROUTER\tUNDERLAY\tUP\t"+
+		"LATENCY MS\tJITTER MS\tLOSS %\tREASON")
+	for _, v := range violations {
+		if v.path == nil {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\t-\t-\t-\t%s\n", v.srcVpcID, v.dstVpcID,
+				v.reason)
+			continue
+		}
+		pathChar := v.path.GetPathChar()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n", v.srcVpcID, v.dstVpcID,
+			v.path.GetLocalRtrName().GetValue(), v.path.GetRemoteRtrName().GetValue(),
+			getUnderlayTypeName(v.path.GetKey().GetUlT()),
+			strconv.FormatBool(pathChar.GetUp().GetValue()), pathChar.GetLatencyMs().GetValue(),
+			pathChar.GetJitterMs().GetValue(), pathChar.GetPktLossPc().GetValue(), v.reason)
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// waitForPathSLA polls the paths of the topology with getPaths until they
+// meet the SLA
+func waitForPathSLA(getPaths func(*cdv1_api.Path) ([]*cdv1_api.Path, error),
+	d *schema.ResourceData, timeout time.Duration) error {
+	topologyName := d.Get("topology_name").(string)
+	sla := getPathSLA(d)
+	filter := &cdv1_api.Path{
+		TopologyName: &wrappers.StringValue{Value: topologyName},
+	}
+
+	var violations []pathSLAViolation
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		paths, err := getPaths(filter)
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+		violations = sla.check(paths)
+		if len(violations) != 0 {
+			return resource.RetryableError(fmt.Errorf("%d paths of topology %s don't meet "+
+				"the SLA", len(violations), topologyName))
+		}
+		return nil
+	})
+	if err != nil && len(violations) != 0 {
+		return fmt.Errorf("Paths of topology %s didn't meet the SLA within %s:\n%s",
+			topologyName, timeout, formatPathSLAViolations(violations))
+	}
+	return err
+}
+
+func cloudeosPathSlaCreate(d *schema.ResourceData, m interface{}) error {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
+	if err := waitForPathSLA(provider.GetAllPaths, d,
+		d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	d.SetId(resource.PrefixedUniqueId("cloudeos-path-sla-" +
+		d.Get("topology_name").(string) + "-"))
+	log.Print("Paths of " + d.Get("topology_name").(string) + " meet the SLA")
+	return nil
+}
+
+// The paths are only checked on create and update, a path going down later
+// isn't a change of the resource
+func cloudeosPathSlaRead(d *schema.ResourceData, m interface{}) error {
+	return nil
+}
+
+func cloudeosPathSlaUpdate(d *schema.ResourceData, m interface{}) error {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
+	return updatePathSLA(provider.GetAllPaths, d, d.Timeout(schema.TimeoutUpdate))
+}
+
+// updatePathSLA waits for the paths to meet the updated SLA. The state keeps
+// the previous paths and thresholds when they don't, so that the next apply
+// checks them again.
+func updatePathSLA(getPaths func(*cdv1_api.Path) ([]*cdv1_api.Path, error),
+	d *schema.ResourceData, timeout time.Duration) error {
+	d.Partial(true)
+	if err := waitForPathSLA(getPaths, d, timeout); err != nil {
+		return err
+	}
+	d.Partial(false)
+	return nil
+}
+
+func cloudeosPathSlaDelete(d *schema.ResourceData, m interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	r "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestResourcePathSla(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config:      testResourcePathSlaInvalidLossConfig,
+				ExpectError: regexp.MustCompile("expected max_pkt_loss_pc to be in the range"),
+			},
+			{
+				Config: testResourcePathSlaConfig,
+				Check:  testResourcePathSlaCheck,
+			},
+		},
+	})
+}
+
+var testResourcePathSlaInvalidLossConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+resource "cloudeos_path_sla" "sla" {
+  topology_name   = "topo-test2"
+  max_pkt_loss_pc = 101
+  path {
+    src_vpc_id = "vpc-0d981c28a83c3fe55"
+    dst_vpc_id = "vpc-0a5856fd8cb6fbee6"
+  }
+}
+`, os.Getenv("token"))
+
+var testResourcePathSlaConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+resource "cloudeos_path_sla" "sla" {
+  topology_name   = "topo-test2"
+  max_latency_ms  = 200
+  max_pkt_loss_pc = 1
+  path {
+    src_vpc_id = "vpc-0d981c28a83c3fe55"
+    dst_vpc_id = "vpc-0a5856fd8cb6fbee6"
+  }
+}
+`, os.Getenv("token"))
+
+func testResourcePathSlaCheck(s *terraform.State) error {
+	resourceState := s.Modules[0].Resources["cloudeos_path_sla.sla"]
+	if resourceState == nil {
+		return fmt.Errorf("cloudeos_path_sla resource not found in state")
+	}
+	if resourceState.Primary == nil || resourceState.Primary.ID == "" {
+		return fmt.Errorf("cloudeos_path_sla ID not assigned")
+	}
+	return nil
+}
+
+func testPath(src, dst string, up bool, latencyMs int64) *cdv1_api.Path {
+	return &cdv1_api.Path{
+		SrcVpcCloudId: wrapperspb.String(src),
+		DstVpcCloudId: wrapperspb.String(dst),
+		LocalRtrName:  wrapperspb.String(src + "-rtr"),
+		RemoteRtrName: wrapperspb.String(dst + "-rtr"),
+		PathChar: &cdv1_api.PathCharacteristics{
+			Up:        wrapperspb.Bool(up),
+			LatencyMs: wrapperspb.Int64(latencyMs),
+		},
+	}
+}
+
+func TestPathSlaCheck(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, cloudeosPathSla().Schema, map[string]interface{}{
+		"topology_name":   "topo",
+		"max_latency_ms":  100,
+		"max_pkt_loss_pc": 0,
+		"path": []interface{}{
+			map[string]interface{}{"src_vpc_id": "vpc-a", "dst_vpc_id": "vpc-b"},
+			map[string]interface{}{"src_vpc_id": "vpc-a", "dst_vpc_id": "vpc-c"},
+			map[string]interface{}{"src_vpc_id": "vpc-b", "dst_vpc_id": "vpc-c"},
+		},
+	})
+	sla := getPathSLA(d)
+	if sla.maxPktLossPc == nil || *sla.maxPktLossPc != 0 {
+		t.Fatalf("max_pkt_loss_pc of 0 not checked")
+	}
+	if sla.maxJitterMs != nil {
+		t.Fatalf("Unset max_jitter_ms checked")
+	}
+
+	paths := []*cdv1_api.Path{
+		testPath("vpc-a", "vpc-b", true, 10),
+		testPath("vpc-a", "vpc-c", true, 150),
+		testPath("vpc-a", "vpc-c", false, 10),
+		testPath("vpc-c", "vpc-a", true, 10),
+	}
+	var got []string
+	for _, v := range sla.check(paths) {
+		got = append(got, v.srcVpcID+">"+v.dstVpcID+": "+v.reason)
+	}
+	want := []string{
+		"vpc-a>vpc-c: latency above 100ms",
+		"vpc-a>vpc-c: down",
+		"vpc-b>vpc-c: no path",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	table := formatPathSLAViolations(sla.check(paths))
+	for _, s := range []string{"SRC VPC", "vpc-a-rtr", "latency above 100ms", "no path"} {
+		if !strings.Contains(table, s) {
+			t.Errorf("Table doesn't contain %q:\n%s", s, table)
+		}
+	}
+	if violations := sla.check(paths[:1]); len(violations) != 2 {
+		t.Errorf("Got %d violations; want 2", len(violations))
+	}
+}
+
+func TestPathSlaFailedUpdate(t *testing.T) {
+	res := cloudeosPathSla()
+	res.Update = func(d *schema.ResourceData, m interface{}) error {
+		return updatePathSLA(func(*cdv1_api.Path) ([]*cdv1_api.Path, error) {
+			return []*cdv1_api.Path{testPath("vpc-a", "vpc-c", false, 10)}, nil
+		}, d, time.Millisecond)
+	}
+	state := &terraform.InstanceState{
+		ID: "cloudeos-path-sla-topo-1",
+		Attributes: map[string]string{
+			"id":                "cloudeos-path-sla-topo-1",
+			"topology_name":     "topo",
+			"max_latency_ms":    "100",
+			"path.#":            "1",
+			"path.0.src_vpc_id": "vpc-a",
+			"path.0.dst_vpc_id": "vpc-b",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"topology_name":  "topo",
+		"max_latency_ms": 50,
+		"path": []interface{}{
+			map[string]interface{}{"src_vpc_id": "vpc-a", "dst_vpc_id": "vpc-c"},
+		},
+	})
+	diff, err := res.Diff(state, config, nil)
+	if err != nil {
+		t.Fatalf("Failed to diff: %s", err)
+	}
+
+	// A failed update keeps the previous SLA, so that it's checked again
+	newState, err := res.Apply(state, diff, nil)
+	if err == nil || !strings.Contains(err.Error(), "didn't meet the SLA") {
+		t.Fatalf("Got error %v; want the SLA not met", err)
+	}
+	for attr, want := range map[string]string{
+		"max_latency_ms":    "100",
+		"path.0.dst_vpc_id": "vpc-b",
+	} {
+		if got := newState.Attributes[attr]; got != want {
+			t.Errorf("%s is %q; want %q", attr, got, want)
+		}
+	}
+}
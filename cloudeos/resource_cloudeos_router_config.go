@@ -7,13 +7,16 @@ package cloudeos
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 func cloudeosRouterConfig() *schema.Resource {
@@ -23,11 +26,29 @@ func cloudeosRouterConfig() *schema.Resource {
 		Update: cloudeosRouterConfigUpdate,
 		Delete: cloudeosRouterConfigDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: cloudeosRouterConfigImport,
+		},
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    cloudeosRouterConfigV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: cloudeosRouterConfigStateUpgradeV0,
+			},
+			{
+				Version: 1,
+				Type:    cloudeosRouterConfigV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: cloudeosRouterConfigStateUpgradeV1,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"cloud_provider": {
 				Type:        schema.TypeString,
@@ -41,7 +62,6 @@ func cloudeosRouterConfig() *schema.Resource {
 					}
 					return
 				},
-				DiffSuppressFunc: suppressAttributeChange,
 			},
 			"cnps": {
 				Optional: true,
@@ -52,9 +72,8 @@ func cloudeosRouterConfig() *schema.Resource {
 				Type:     schema.TypeString,
 			},
 			"topology_name": {
-				Required:         true,
-				Type:             schema.TypeString,
-				DiffSuppressFunc: suppressAttributeChange,
+				Required: true,
+				Type:     schema.TypeString,
 			},
 			"tags": {
 				Type:        schema.TypeMap,
@@ -94,11 +113,10 @@ func cloudeosRouterConfig() *schema.Resource {
 				ForceNew:    true,
 			},
 			"cloudeos_image_offer": {
-				Optional:         true,
-				Description:      "CloudEos Licensing Model",
-				Type:             schema.TypeString,
-				DiffSuppressFunc: suppressAttributeChange,
-				Default:          "cloudeos-router-payg",
+				Optional:    true,
+				Description: "CloudEos Licensing Model",
+				Type:        schema.TypeString,
+				Default:     "cloudeos-router-payg",
 				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
 					v := val.(string)
 					if v != "cloudeos-router-byol" && v != "cloudeos-router-payg" {
@@ -140,40 +158,49 @@ func cloudeosRouterConfig() *schema.Resource {
 				Type:     schema.TypeString,
 				ForceNew: true,
 			},
-			"intf_name": {
+			"interface": {
 				Type:        schema.TypeList,
 				Required:    true,
-				Description: "Interface name",
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    1,
+				Description: "Network interfaces of the router",
+				Elem:        cloudeosRouterIntfSchema(false),
 			},
-			"intf_private_ip": {
-				Type:        schema.TypeList,
-				Required:    true,
-				Description: "Private IP address",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				ForceNew:    true,
+			"peer_routetable_id": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"enrollment_token_valid_for": {
+				Optional:     true,
+				Type:         schema.TypeString,
+				Default:      "2h",
+				ValidateFunc: validateDuration,
+				Description:  "Validity of the enrollment token in the bootstrap_cfg, e.g. 2h",
 			},
-			"intf_type": {
+			"enrollment_token_groups": {
+				Optional:    true,
 				Type:        schema.TypeList,
-				Required:    true,
-				Description: "Interface type",
 				Elem:        &schema.Schema{Type: schema.TypeString},
-				ForceNew:    true,
+				Description: "Device groups the router is added to when it enrolls",
 			},
-			"peerroutetableid1": {
-				Type:       schema.TypeList,
-				Computed:   true,
-				Elem:       &schema.Schema{Type: schema.TypeString},
-				Deprecated: "This attribute is deprecated, use peer_routetable_id",
-			},
-			"peer_routetable_id": {
+			"enrollment_token_reenroll_devices": {
+				Optional: true,
 				Type:     schema.TypeList,
-				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: "Serial numbers of the devices allowed to re-enroll with the" +
+					" enrollment token, \"*\" allows any device. Defaults to the device of" +
+					" the router, if it already enrolled",
 			},
 			"bootstrap_cfg": {
-				Computed: true,
+				Computed:  true,
+				Type:      schema.TypeString,
+				Sensitive: true,
+			},
+			"bootstrap_cfg_file": {
+				Optional: true,
 				Type:     schema.TypeString,
+				Description: "Local file the bootstrap configuration is written to, with 0600" +
+					" permissions, instead of being kept in bootstrap_cfg",
 			},
 			"ha_rtr_id": {
 				Computed: true,
@@ -203,80 +230,96 @@ func cloudeosRouterConfig() *schema.Resource {
 				Computed: true,
 				Optional: true,
 			},
+			"replace_on_change": replaceOnChangeSchema(),
 		},
-		CustomizeDiff: func(d *schema.ResourceDiff, m interface{}) error {
-			oldoffer, offer := d.GetChange("cloudeos_image_offer")
-			// LicenseType : Compulsory map
-			licenseNeeded := map[string]bool{
-				"ipsec":     true,
-				"bandwidth": true,
-			}
+		CustomizeDiff: customdiff.All(
+			immutableAttributes("cloudeos_router_config", "cloud_provider",
+				"topology_name", "cloudeos_image_offer"),
+			cloudeosRouterConfigCustomizeDiff,
+			gcpZoneInRegion("cloudeos_router_config"),
+		),
+	}
+}
 
-			// Validate licenses here, as TypeSet doesn't support validateFunc.
-			if v, ok := d.GetOk("licenses"); ok {
-				// Licenses should be specified only with BYOL
-				if offer != "cloudeos-router-byol" {
-					return fmt.Errorf("Licenses not supported when using PAYG. Are you sure you are using correct cloudeos-image-offer")
+func cloudeosRouterConfigCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	// bootstrap_cfg is emptied or filled in when moved to or from a file
+	if d.Id() != "" && d.HasChange("bootstrap_cfg_file") {
+		if err := d.SetNewComputed("bootstrap_cfg"); err != nil {
+			return err
+		}
+	}
+
+	oldoffer, offer := d.GetChange("cloudeos_image_offer")
+	// LicenseType : Compulsory map
+	licenseNeeded := map[string]bool{
+		"ipsec":     true,
+		"bandwidth": true,
+	}
+
+	// Validate licenses here, as TypeSet doesn't support validateFunc.
+	if v, ok := d.GetOk("licenses"); ok {
+		// Licenses should be specified only with BYOL
+		if offer != "cloudeos-router-byol" {
+			return fmt.Errorf("Licenses not supported when using PAYG. Are you sure you are using correct cloudeos-image-offer")
+		}
+		licenseList := v.(*schema.Set).List()
+		for _, k := range licenseList {
+			license := k.(map[string]interface{})
+			licenseType := license["type"].(string)
+			if _, ok := licenseNeeded[licenseType]; ok {
+				licenseNeeded[licenseType] = false
+				_, err := os.Stat(license["path"].(string))
+				if err != nil {
+					return fmt.Errorf(" License %s, Unable to open file %q", licenseType, license["path"])
 				}
-				licenseList := v.(*schema.Set).List()
-				for _, k := range licenseList {
-					license := k.(map[string]interface{})
-					licenseType := license["type"].(string)
-					if _, ok := licenseNeeded[licenseType]; ok {
-						licenseNeeded[licenseType] = false
-						_, err := os.Stat(license["path"].(string))
-						if err != nil {
-							return fmt.Errorf(" License %s, Unable to open file %q", licenseType, license["path"])
-						}
-					} else {
-						supportedLicenses := " "
-						for k, _ := range licenseNeeded {
-							supportedLicenses += k + ", "
-						}
-						return fmt.Errorf("%s license isn't supported. Supported Licenses : [%s]", licenseType, supportedLicenses)
-					}
+			} else {
+				supportedLicenses := " "
+				for k, _ := range licenseNeeded {
+					supportedLicenses += k + ", "
 				}
+				return fmt.Errorf("%s license isn't supported. Supported Licenses : [%s]", licenseType, supportedLicenses)
 			}
+		}
+	}
 
-			if offer == "cloudeos-router-byol" {
+	if offer == "cloudeos-router-byol" {
 
-				// Plugin upgraded for already deployed topology
-				oldCloudProvider, _ := d.GetChange("cloud_provider")
-				if oldCloudProvider != "" && oldoffer == "" {
-					return fmt.Errorf("Already exists payg topology, destroy it first before changing cloudeos_image_offer to byol")
+		// Plugin upgraded for already deployed topology
+		oldCloudProvider, _ := d.GetChange("cloud_provider")
+		if oldCloudProvider != "" && oldoffer == "" {
+			return fmt.Errorf("Already exists payg topology, destroy it first before changing cloudeos_image_offer to byol")
 
-				}
+		}
 
-				// Some licenses are compulsory, check they are present
-				missingLicenses := " "
-				for k, v := range licenseNeeded {
-					if v == true {
-						missingLicenses += k + ", "
-					}
-				}
-				if missingLicenses != " " {
-					return fmt.Errorf("[%s] license needs to be specified when using BYOL", missingLicenses)
-				}
+		// Some licenses are compulsory, check they are present
+		missingLicenses := " "
+		for k, v := range licenseNeeded {
+			if v == true {
+				missingLicenses += k + ", "
+			}
+		}
+		if missingLicenses != " " {
+			return fmt.Errorf("[%s] license needs to be specified when using BYOL", missingLicenses)
+		}
 
-				// Check if license attribute is updated
-				oldLicenses, newLicenses := d.GetChange("licenses")
-				if oldoffer == "cloudeos-router-byol" && oldLicenses != nil && newLicenses != nil {
-					oldLicensesSet := oldLicenses.(*schema.Set)
-					newLicensesSet := newLicenses.(*schema.Set)
-					if !oldLicensesSet.Equal(newLicensesSet) {
-						log.Printf("Attribute Change: licenses \n[old] : %#v \n[new] : %#v", oldLicensesSet, newLicensesSet)
-						return fmt.Errorf("Updating Licenses is not supported, you need to destroy first or make changes through CVaaS")
-					}
-				}
+		// Check if license attribute is updated
+		oldLicenses, newLicenses := d.GetChange("licenses")
+		if oldoffer == "cloudeos-router-byol" && oldLicenses != nil && newLicenses != nil {
+			oldLicensesSet := oldLicenses.(*schema.Set)
+			newLicensesSet := newLicenses.(*schema.Set)
+			if !oldLicensesSet.Equal(newLicensesSet) {
+				log.Printf("Attribute Change: licenses \n[old] : %#v \n[new] : %#v", oldLicensesSet, newLicensesSet)
+				return fmt.Errorf("Updating Licenses is not supported, you need to destroy first or make changes through CVaaS")
 			}
-			return nil
-		},
+		}
 	}
+	return nil
 }
 
 func cloudeosRouterConfigCreate(d *schema.ResourceData, m interface{}) error {
 	//TBD: Call ListVpc to get deployment type( not needed for EFT )
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
 
 	//Retry ListVpc to check VPC is present in Aeris before Router.
 	var rtrDeployMode string
@@ -335,6 +378,15 @@ func cloudeosRouterConfigCreate(d *schema.ResourceData, m interface{}) error {
 		}
 		return errors.New("bootstrap config wasn't returned by CVP.(Try terraform apply again)")
 	}
+	if path := d.Get("bootstrap_cfg_file").(string); path != "" {
+		if err := moveBootstrapCfgToFile(d, d.Get("bootstrap_cfg").(string), path); err != nil {
+			// The router isn't in the state yet, don't leave it behind in CVaaS
+			if cleanupErr := provider.DeleteRouter(d); cleanupErr != nil {
+				return fmt.Errorf("%v, failed during cleanup: %v", err, cleanupErr)
+			}
+			return err
+		}
+	}
 
 	uuid := "cloudeos-router-config" + strings.TrimPrefix(d.Get("tf_id").(string), RtrPrefix)
 	log.Print("Successfully added " + uuid)
@@ -343,11 +395,40 @@ func cloudeosRouterConfigCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func cloudeosRouterConfigRead(d *schema.ResourceData, m interface{}) error {
-	return nil
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+	resp, err := provider.GetRouterResponse(d)
+	if err != nil {
+		return err
+	}
+
+	uuid := "cloudeos-router-config" + strings.TrimPrefix(d.Get("tf_id").(string), RtrPrefix)
+	ent := resp.GetValue()
+	if removeIfGone(d, ent.GetKey().GetId().GetValue() != "", uuid) {
+		return nil
+	}
+
+	// bootstrap_cfg is only set on create. It is consumed as the user data of
+	// the router instance, refreshing it would force the instance to be
+	// replaced.
+	if err := parseRtrCvInfo(ent, d); err != nil {
+		return err
+	}
+	if err := parseRtrIntfResponse(ent, d, false); err != nil {
+		return err
+	}
+	return setDeployMode(d, strings.ToLower(ent.GetDeployMode().GetValue()))
 }
 
 func cloudeosRouterConfigUpdate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
+
+	if d.HasChange("bootstrap_cfg_file") {
+		if err := updateBootstrapCfgFile(d); err != nil {
+			return err
+		}
+	}
 
 	err := provider.AddRouterConfig(d)
 	if err != nil {
@@ -361,6 +442,7 @@ func cloudeosRouterConfigUpdate(d *schema.ResourceData, m interface{}) error {
 
 func cloudeosRouterConfigDelete(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutDelete))
 	err := provider.DeleteRouter(d)
 	if err != nil {
 		return err
@@ -382,3 +464,291 @@ func cloudeosRouterConfigDelete(d *schema.ResourceData, m interface{}) error {
 	d.SetId("")
 	return nil
 }
+
+func cloudeosRouterConfigImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData,
+	error) {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	// The import ID is either the tf_id or <vpc_id>/<name> of the router,
+	// where name is the Name tag of the router
+	tfID := d.Id()
+	if !strings.HasPrefix(tfID, RtrPrefix) {
+		sep := strings.LastIndex(tfID, "/")
+		if sep <= 0 || sep == len(tfID)-1 {
+			return nil, fmt.Errorf("Unexpected import ID %q, expected a tf_id or "+
+				"<vpc_id>/<name>", tfID)
+		}
+		rtr, err := provider.GetRouterByName(tfID[:sep], tfID[sep+1:])
+		if err != nil {
+			return nil, err
+		}
+		tfID = rtr.GetKey().GetId().GetValue()
+	}
+	if err := d.Set("tf_id", tfID); err != nil {
+		return nil, err
+	}
+	if err := importReplaceOnChange(d); err != nil {
+		return nil, err
+	}
+	if err := d.Set("enrollment_token_valid_for", "2h"); err != nil {
+		return nil, err
+	}
+
+	resp, err := provider.GetRouterResponse(d)
+	if err != nil {
+		return nil, err
+	}
+	ent := resp.GetValue()
+	if ent.GetKey().GetId().GetValue() == "" {
+		return nil, fmt.Errorf("cloudeos_router_config %s not found in CVaaS", d.Id())
+	}
+
+	// topology_name and role are derived from the vpc of the router
+	vpc, err := provider.GetVpcByVpcID(ent.GetVpcId().GetValue())
+	if err != nil {
+		return nil, err
+	}
+	if err := parseRtrImport(ent, vpc, d, false); err != nil {
+		return nil, err
+	}
+	if err := setBootStrapCfg(d, ent.GetCvInfo().GetBootstrapCfg().GetValue()); err != nil {
+		return nil, err
+	}
+
+	d.SetId("cloudeos-router-config" + strings.TrimPrefix(tfID, RtrPrefix))
+	return []*schema.ResourceData{d}, nil
+}
+
+// moveBootstrapCfgToFile writes the bootstrap configuration to path, only
+// readable by the user, and removes it from the state
+func moveBootstrapCfgToFile(d *schema.ResourceData, cfg, path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write bootstrap_cfg_file %s: %v", path, err)
+	}
+	defer f.Close()
+	// The permissions of an existing file are left as is by OpenFile
+	if err := f.Chmod(0600); err != nil {
+		return fmt.Errorf("Failed to write bootstrap_cfg_file %s: %v", path, err)
+	}
+	if _, err := f.WriteString(cfg); err != nil {
+		return fmt.Errorf("Failed to write bootstrap_cfg_file %s: %v", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Failed to write bootstrap_cfg_file %s: %v", path, err)
+	}
+	return d.Set("bootstrap_cfg", "")
+}
+
+// updateBootstrapCfgFile moves the bootstrap configuration created with the
+// router from the state or the previous bootstrap_cfg_file to the new one, or
+// back to the state when bootstrap_cfg_file is removed
+func updateBootstrapCfgFile(d *schema.ResourceData) error {
+	oldPath, newPath := d.GetChange("bootstrap_cfg_file")
+	cfg, _ := d.GetChange("bootstrap_cfg")
+	if oldPath.(string) != "" {
+		content, err := ioutil.ReadFile(oldPath.(string))
+		if err != nil {
+			return fmt.Errorf("Failed to read bootstrap_cfg_file %s: %v", oldPath, err)
+		}
+		cfg = string(content)
+	}
+
+	if newPath.(string) == "" {
+		if err := d.Set("bootstrap_cfg", cfg); err != nil {
+			return err
+		}
+	} else if err := moveBootstrapCfgToFile(d, cfg.(string), newPath.(string)); err != nil {
+		return err
+	}
+	if oldPath.(string) != "" {
+		return os.Remove(oldPath.(string))
+	}
+	return nil
+}
+
+// cloudeosRouterIntfSchema defines the interface blocks of the router
+// resources. intf_id is only part of cloudeos_router_status, which also
+// requires the subnet of the interfaces. The interfaces of
+// cloudeos_router_config are created with the router, so their public IP,
+// subnet and security group are filled in by cloudeos_router_status when not
+// set.
+func cloudeosRouterIntfSchema(isRtrStatus bool) *schema.Resource {
+	intfSchema := map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Interface name",
+		},
+		"type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     !isRtrStatus,
+			ValidateFunc: validation.StringInSlice([]string{"public", "private", "internal"}, true),
+			Description:  "Interface type: public, private or internal",
+		},
+		"private_ips": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MinItems:    1,
+			ForceNew:    !isRtrStatus,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Private IP addresses of the interface, the first one is the primary IP",
+		},
+		"public_ip": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    !isRtrStatus,
+			Description: "Public IP address of the interface",
+		},
+		"subnet_id": {
+			Type:        schema.TypeString,
+			Optional:    !isRtrStatus,
+			Required:    isRtrStatus,
+			Computed:    !isRtrStatus,
+			Description: "Subnet id attached to the interface",
+		},
+		"security_group": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    !isRtrStatus,
+			Description: "Security group attached to the interface",
+		},
+	}
+	if isRtrStatus {
+		intfSchema["intf_id"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Interface id",
+		}
+	}
+	return &schema.Resource{Schema: intfSchema}
+}
+
+// cloudeosRouterConfigV0 is the schema of cloudeos_router_config before the
+// intf_* lists were replaced by interface blocks
+func cloudeosRouterConfigV0() *schema.Resource {
+	v0 := legacyStateSchema(map[string]schema.ValueType{
+		"cloud_provider":                    schema.TypeString,
+		"cnps":                              schema.TypeString,
+		"region":                            schema.TypeString,
+		"topology_name":                     schema.TypeString,
+		"tags":                              schema.TypeMap,
+		"vpc_id":                            schema.TypeString,
+		"role":                              schema.TypeString,
+		"is_rr":                             schema.TypeBool,
+		"ami":                               schema.TypeString,
+		"cloudeos_image_offer":              schema.TypeString,
+		"key_name":                          schema.TypeString,
+		"availability_zone":                 schema.TypeString,
+		"intf_name":                         schema.TypeList,
+		"intf_private_ip":                   schema.TypeList,
+		"intf_type":                         schema.TypeList,
+		"peerroutetableid1":                 schema.TypeList,
+		"peer_routetable_id":                schema.TypeList,
+		"enrollment_token_valid_for":        schema.TypeString,
+		"enrollment_token_groups":           schema.TypeList,
+		"enrollment_token_reenroll_devices": schema.TypeList,
+		"bootstrap_cfg":                     schema.TypeString,
+		"bootstrap_cfg_file":                schema.TypeString,
+		"ha_rtr_id":                         schema.TypeString,
+		"public_rt_table_id":                schema.TypeList,
+		"internal_rt_table_id":              schema.TypeList,
+		"private_rt_table_id":               schema.TypeList,
+		"tf_id":                             schema.TypeString,
+		"deploy_mode":                       schema.TypeString,
+		"replace_on_change":                 schema.TypeBool,
+	})
+	v0["licenses"] = &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: legacyStateSchema(map[string]schema.ValueType{
+				"type": schema.TypeString,
+				"path": schema.TypeString,
+				"hash": schema.TypeString,
+			}),
+		},
+	}
+	return &schema.Resource{Schema: v0}
+}
+
+// cloudeosRouterConfigV1 is the schema of cloudeos_router_config before the
+// deprecated peerroutetableid1 was removed
+func cloudeosRouterConfigV1() *schema.Resource {
+	v1 := cloudeosRouterConfigV0()
+	for _, attr := range []string{"intf_name", "intf_private_ip", "intf_type"} {
+		delete(v1.Schema, attr)
+	}
+	v1.Schema["interface"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem:     cloudeosRouterIntfSchema(false),
+	}
+	return v1
+}
+
+// cloudeosRouterConfigStateUpgradeV0 moves the intf_* lists to interface
+// blocks
+func cloudeosRouterConfigStateUpgradeV0(rawState map[string]interface{},
+	meta interface{}) (map[string]interface{}, error) {
+	return routerIntfStateUpgradeV0(rawState, false), nil
+}
+
+// cloudeosRouterConfigStateUpgradeV1 removes peerroutetableid1, which is
+// replaced by peer_routetable_id
+func cloudeosRouterConfigStateUpgradeV1(rawState map[string]interface{},
+	meta interface{}) (map[string]interface{}, error) {
+	return upgradeDeprecatedAttribute(rawState, "peerroutetableid1", "peer_routetable_id"), nil
+}
+
+// routerIntfStateUpgradeV0 moves the intf_* lists of a router resource to
+// interface blocks, one per intf_name. public_ip, which only applied to the
+// first interface, is moved to it for cloudeos_router_status.
+func routerIntfStateUpgradeV0(rawState map[string]interface{},
+	isRtrStatus bool) map[string]interface{} {
+	legacyAttrs := map[string]string{
+		"name": "intf_name",
+		"type": "intf_type",
+	}
+	if isRtrStatus {
+		legacyAttrs["intf_id"] = "intf_id"
+		legacyAttrs["subnet_id"] = "intf_subnet_id"
+	}
+	// The lists may be shorter than intf_name
+	listValue := func(attr string, i int) interface{} {
+		values, _ := rawState[attr].([]interface{})
+		if i < len(values) && values[i] != nil {
+			return values[i]
+		}
+		return ""
+	}
+
+	names, _ := rawState["intf_name"].([]interface{})
+	var intfs []interface{}
+	for i := range names {
+		intf := map[string]interface{}{}
+		for attr, legacyAttr := range legacyAttrs {
+			intf[attr] = listValue(legacyAttr, i)
+		}
+		intf["private_ips"] = []interface{}{}
+		if privateIP := listValue("intf_private_ip", i); privateIP != "" {
+			intf["private_ips"] = []interface{}{privateIP}
+		}
+		if isRtrStatus && i == 0 {
+			intf["public_ip"] = rawState["public_ip"]
+		}
+		intfs = append(intfs, intf)
+	}
+
+	delete(rawState, "intf_private_ip")
+	for _, legacyAttr := range legacyAttrs {
+		delete(rawState, legacyAttr)
+	}
+	if isRtrStatus {
+		delete(rawState, "public_ip")
+	}
+	rawState["interface"] = intfs
+	return rawState
+}
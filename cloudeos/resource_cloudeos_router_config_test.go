@@ -6,10 +6,15 @@ package cloudeos
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"testing"
 
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
 	r "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
@@ -117,9 +122,16 @@ resource "cloudeos_router_config" "cloudeos" {
   ami = "dummy-aws-machine-image"
   key_name = "foo"
   availability_zone = "us-west-1c"
-  intf_name = ["edgecloudeos1Intf0", "edgecloudeos1Intf1"]
-  intf_private_ip = ["11.0.0.101", "11.0.1.101"]
-  intf_type = ["public", "internal"]
+  interface {
+    name = "edgecloudeos1Intf0"
+    type = "public"
+    private_ips = ["11.0.0.101"]
+  }
+  interface {
+    name = "edgecloudeos1Intf1"
+    type = "internal"
+    private_ips = ["11.0.1.101"]
+  }
   deploy_mode = "provision"
 }
 `, os.Getenv("token"))
@@ -216,9 +228,16 @@ resource "cloudeos_router_config" "cloudeos" {
   ami = "dummy-aws-machine-image"
   key_name = "foo"
   availability_zone = "us-west-1c"
-  intf_name = ["edgecloudeos1Intf0", "edgecloudeos1Intf1"]
-  intf_private_ip = ["11.0.0.101", "11.0.1.101"]
-  intf_type = ["public", "internal"]
+  interface {
+    name = "edgecloudeos1Intf0"
+    type = "public"
+    private_ips = ["11.0.0.101"]
+  }
+  interface {
+    name = "edgecloudeos1Intf1"
+    type = "internal"
+    private_ips = ["11.0.1.101"]
+  }
 }
 `, os.Getenv("token"))
 
@@ -339,9 +358,16 @@ resource "cloudeos_router_config" "cloudeos" {
   ami = "dummy-aws-machine-image"
   key_name = "foo"
   availability_zone = "us-west-1c"
-  intf_name = ["edgecloudeos1Intf0", "edgecloudeos1Intf1"]
-  intf_private_ip = ["11.0.0.101", "11.0.1.101"]
-  intf_type = ["public", "internal"]
+  interface {
+    name = "edgecloudeos1Intf0"
+    type = "public"
+    private_ips = ["11.0.0.101"]
+  }
+  interface {
+    name = "edgecloudeos1Intf1"
+    type = "internal"
+    private_ips = ["11.0.1.101"]
+  }
 }
 `, os.Getenv("token"))
 
@@ -376,3 +402,194 @@ func testResourceRouterConfigDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestBootstrapCfgFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bootstrap.cfg")
+	// An existing file gets its permissions restricted
+	if err := ioutil.WriteFile(path, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := cloudeosRouterConfig().TestResourceData()
+	d.Set("bootstrap_cfg", "cfg")
+	if err := moveBootstrapCfgToFile(d, "cfg", path); err != nil {
+		t.Fatalf("Failed to write bootstrap_cfg_file: %s", err)
+	}
+	if got := d.Get("bootstrap_cfg").(string); got != "" {
+		t.Errorf("bootstrap_cfg kept in state: %q", got)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "cfg" {
+		t.Errorf("bootstrap_cfg_file contains %q; want %q", content, "cfg")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("bootstrap_cfg_file has permissions %v; want 0600", perm)
+	}
+}
+
+func TestRouterConfigStateUpgradeV0(t *testing.T) {
+	v0 := map[string]interface{}{
+		"tf_id":           "rtr-1",
+		"intf_name":       []interface{}{"edgecloudeos1Intf0", "edgecloudeos1Intf1"},
+		"intf_private_ip": []interface{}{"11.0.0.101", "11.0.1.101"},
+		"intf_type":       []interface{}{"public", "internal"},
+	}
+	want := map[string]interface{}{
+		"tf_id": "rtr-1",
+		"interface": []interface{}{
+			map[string]interface{}{
+				"name":        "edgecloudeos1Intf0",
+				"type":        "public",
+				"private_ips": []interface{}{"11.0.0.101"},
+			},
+			map[string]interface{}{
+				"name":        "edgecloudeos1Intf1",
+				"type":        "internal",
+				"private_ips": []interface{}{"11.0.1.101"},
+			},
+		},
+	}
+	got, err := cloudeosRouterConfigStateUpgradeV0(v0, nil)
+	if err != nil {
+		t.Fatalf("Failed to upgrade state: %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Upgraded state %v; want %v", got, want)
+	}
+
+	// Lists shorter than intf_name used to panic when creating the router
+	v0 = map[string]interface{}{
+		"intf_name":       []interface{}{"edgecloudeos1Intf0", "edgecloudeos1Intf1"},
+		"intf_private_ip": []interface{}{"11.0.0.101"},
+		"intf_type":       []interface{}{"public"},
+	}
+	got, err = cloudeosRouterConfigStateUpgradeV0(v0, nil)
+	if err != nil {
+		t.Fatalf("Failed to upgrade state: %s", err)
+	}
+	wantIntf := map[string]interface{}{
+		"name":        "edgecloudeos1Intf1",
+		"type":        "",
+		"private_ips": []interface{}{},
+	}
+	if intf := got["interface"].([]interface{})[1]; !reflect.DeepEqual(intf, wantIntf) {
+		t.Errorf("Upgraded interface %v; want %v", intf, wantIntf)
+	}
+}
+
+func TestGetRouterIntfs(t *testing.T) {
+	d := cloudeosRouterStatus().TestResourceData()
+	d.Set("interface", []interface{}{
+		map[string]interface{}{
+			"name":           "publicIntf",
+			"intf_id":        "eni-1",
+			"type":           "Public",
+			"private_ips":    []interface{}{"10.0.0.101", "10.0.0.102"},
+			"public_ip":      "3.3.3.3",
+			"subnet_id":      "subnet-1",
+			"security_group": "sg-1",
+		},
+		map[string]interface{}{
+			"name":        "internalIntf",
+			"intf_id":     "eni-2",
+			"type":        "internal",
+			"private_ips": []interface{}{"10.0.1.101"},
+			"subnet_id":   "subnet-2",
+		},
+	})
+
+	intfs := getRouterIntfs(d.Get("interface").([]interface{}))
+	if len(intfs) != 2 {
+		t.Fatalf("Got %d interfaces; want 2", len(intfs))
+	}
+	if got := intfs[0].GetIntfType(); got != cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_PUBLIC {
+		t.Errorf("Interface type is %v; want public", got)
+	}
+	if got := intfs[0].GetPrivateIpAddr().GetValues(); !reflect.DeepEqual(got,
+		[]string{"10.0.0.101", "10.0.0.102"}) {
+		t.Errorf("Private IPs are %v", got)
+	}
+	// Any interface can have a public IP and a security group
+	for attr, got := range map[string]string{
+		"intf_id":        intfs[0].GetIntfId().GetValue(),
+		"public_ip":      intfs[0].GetPublicIpAddr().GetValue(),
+		"subnet_id":      intfs[0].GetSubnet().GetValue(),
+		"security_group": intfs[0].GetSecurityGroup().GetValue(),
+	} {
+		if want := d.Get("interface.0." + attr).(string); got != want {
+			t.Errorf("%s is %q; want %q", attr, got, want)
+		}
+	}
+	if got := intfs[1].GetPublicIpAddr().GetValue(); got != "" {
+		t.Errorf("Public IP of the internal interface is %q; want empty", got)
+	}
+
+	// The interfaces are set back from the response
+	rtr := &cdv1_api.RouterConfig{
+		Intf: &cdv1_api.RepeatedNetworkInterfaces{Values: intfs},
+	}
+	if err := parseRtrIntfResponse(rtr, d, true); err != nil {
+		t.Fatalf("Failed to parse the interfaces: %s", err)
+	}
+	if got := d.Get("interface.0.type").(string); got != "Public" {
+		t.Errorf("Interface type is %q; want the configured Public", got)
+	}
+	if got := d.Get("interface.1.intf_id").(string); got != "eni-2" {
+		t.Errorf("intf_id is %q; want eni-2", got)
+	}
+}
+
+func TestRouterConfigStateUpgradeV1(t *testing.T) {
+	// State of a router written by a provider with schema version 0
+	state := map[string]interface{}{
+		"tf_id":              "rtr-1",
+		"intf_name":          []interface{}{"edgecloudeos1Intf0"},
+		"intf_private_ip":    []interface{}{"11.0.0.101"},
+		"intf_type":          []interface{}{"public"},
+		"peerroutetableid1":  []interface{}{"rtb-1"},
+		"peer_routetable_id": []interface{}{"rtb-1"},
+	}
+	want := map[string]interface{}{
+		"tf_id": "rtr-1",
+		"interface": []interface{}{
+			map[string]interface{}{
+				"name":        "edgecloudeos1Intf0",
+				"type":        "public",
+				"private_ips": []interface{}{"11.0.0.101"},
+			},
+		},
+		"peer_routetable_id": []interface{}{"rtb-1"},
+	}
+	for _, upgrader := range cloudeosRouterConfig().StateUpgraders {
+		var err error
+		if state, err = upgrader.Upgrade(state, nil); err != nil {
+			t.Fatalf("Failed to upgrade state from version %d: %s", upgrader.Version, err)
+		}
+	}
+	if !reflect.DeepEqual(state, want) {
+		t.Errorf("Upgraded state %v; want %v", state, want)
+	}
+
+	// States written before peer_routetable_id existed only have peerroutetableid1
+	state = map[string]interface{}{
+		"peerroutetableid1": []interface{}{"rtb-1"},
+	}
+	state, err := cloudeosRouterConfigStateUpgradeV1(state, nil)
+	if err != nil {
+		t.Fatalf("Failed to upgrade state: %s", err)
+	}
+	want = map[string]interface{}{
+		"peer_routetable_id": []interface{}{"rtb-1"},
+	}
+	if !reflect.DeepEqual(state, want) {
+		t.Errorf("Upgraded state %v; want %v", state, want)
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"strings"
 	"time"
 
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -23,10 +26,24 @@ func cloudeosRouterStatus() *schema.Resource {
 		Update: cloudeosRouterStatusUpdate,
 		Delete: cloudeosRouterStatusDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: cloudeosRouterStatusImport,
+		},
+
 		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    cloudeosRouterStatusV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: cloudeosRouterStatusStateUpgradeV0,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"cloud_provider": {
 				Type:        schema.TypeString,
@@ -81,40 +98,12 @@ func cloudeosRouterStatus() *schema.Resource {
 				Type:        schema.TypeString,
 				Description: "Availability set if for Azure",
 			},
-			"public_ip": {
-				Optional:    true,
-				Type:        schema.TypeString,
-				Description: "Public ip address",
-			},
-			"intf_name": {
-				Type:        schema.TypeList,
-				Required:    true,
-				Description: "Interface name",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"intf_id": {
-				Type:        schema.TypeList,
-				Required:    true,
-				Description: "Interface id",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"intf_private_ip": {
-				Type:        schema.TypeList,
-				Required:    true,
-				Description: "Private IP address",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"intf_subnet_id": {
+			"interface": {
 				Type:        schema.TypeList,
 				Required:    true,
-				Description: "Subnet id attached to intf",
-				Elem:        &schema.Schema{Type: schema.TypeString},
-			},
-			"intf_type": {
-				Type:        schema.TypeList,
-				Required:    true,
-				Description: "Interface type",
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    1,
+				Description: "Network interfaces of the router",
+				Elem:        cloudeosRouterIntfSchema(true),
 			},
 			"private_rt_table_ids": {
 				Type:     schema.TypeList,
@@ -170,17 +159,52 @@ func cloudeosRouterStatus() *schema.Resource {
 				Description: "BGP ASN computed on the CloudEOS Router",
 			},
 			"deploy_mode": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				DiffSuppressFunc: suppressAttributeChange,
-				Description:      "Deployment mode for the resources: provision or empty",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Deployment mode for the resources: provision or empty",
+			},
+			"replace_on_change": replaceOnChangeSchema(),
+			"wait_for_ready": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Wait for the CloudEOS Router to be ready in CVaaS when " +
+					"the resource is created",
+			},
+			"cv_status_code": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "CVaaS status of the CloudEOS Router: created, discovered, " +
+					"provisioned, config_wip, ready, failed or inactive",
+			},
+			"cv_status_desc": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Description of the CVaaS status",
+			},
+			"cv_status_recommended_action": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Recommended action for the CVaaS status",
+			},
+			"device_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the device: work_in_progress, success or error",
 			},
 		},
+		CustomizeDiff: customdiff.All(
+			immutableAttributes("cloudeos_router_status", "deploy_mode"),
+			unsupportedAttributes("cloudeos_router_status", "gcp", "rg_name", "rg_location",
+				"availability_set_id"),
+			gcpZoneInRegion("cloudeos_router_status"),
+		),
 	}
 }
 
 func cloudeosRouterStatusCreate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
 	err := provider.AddRouter(d)
 	if err != nil {
 		return err
@@ -209,16 +233,81 @@ func cloudeosRouterStatusCreate(d *schema.ResourceData, m interface{}) error {
 
 	uuid := "cloudeos-router-status" + strings.TrimPrefix(d.Get("tf_id").(string), RtrPrefix)
 	log.Print("Successfully added " + uuid)
+	// Set the ID before waiting, a router which doesn't get ready is tainted
+	// instead of being left behind in CVaaS
 	d.SetId(uuid)
+	if d.Get("wait_for_ready").(bool) {
+		return waitForRouterReady(provider, d, d.Timeout(schema.TimeoutCreate))
+	}
 	return nil
 }
 
+// waitForRouterReady polls the router until CVaaS reports it ready, and fails
+// as soon as CVaaS reports it failed
+func waitForRouterReady(provider CloudeosProvider, d *schema.ResourceData,
+	timeout time.Duration) error {
+	tfID := d.Get("tf_id").(string)
+	return resource.Retry(timeout, func() *resource.RetryError {
+		resp, err := provider.GetRouterResponse(d)
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+		ent := resp.GetValue()
+		if ent.GetKey().GetId().GetValue() == "" {
+			return resource.NonRetryableError(fmt.Errorf("Router %s not found in CVaaS",
+				tfID))
+		}
+		if err := parseRtrCvStatus(ent, d); err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		cvInfo := ent.GetCvInfo()
+		switch cvInfo.GetCvStatusCode() {
+		case cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_READY:
+			return nil
+		case cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_FAILED:
+			return resource.NonRetryableError(fmt.Errorf("Router %s failed: %s. "+
+				"Recommended action: %s", tfID, cvInfo.GetCvStatusDesc().GetValue(),
+				cvInfo.GetCvStatusRecommendedAction().GetValue()))
+		}
+		return resource.RetryableError(fmt.Errorf("Router %s isn't ready, status: %s",
+			tfID, getCvStatusName(cvInfo.GetCvStatusCode())))
+	})
+}
+
 func cloudeosRouterStatusRead(d *schema.ResourceData, m interface{}) error {
-	return nil
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+	resp, err := provider.GetRouterResponse(d)
+	if err != nil {
+		return err
+	}
+
+	uuid := "cloudeos-router-status" + strings.TrimPrefix(d.Get("tf_id").(string), RtrPrefix)
+	ent := resp.GetValue()
+	if removeIfGone(d, ent.GetKey().GetId().GetValue() != "", uuid) {
+		return nil
+	}
+
+	// bgp asn isn't allocated for routers with deploy_mode provision
+	if ent.GetBgpAsn() != nil {
+		routerBgpAsn := fmt.Sprint(ent.GetBgpAsn().GetValue())
+		if err := d.Set("router_bgp_asn", routerBgpAsn); err != nil {
+			return err
+		}
+	}
+	if err := parseRtrIntfResponse(ent, d, true); err != nil {
+		return err
+	}
+	if err := parseRtrCvStatus(ent, d); err != nil {
+		return err
+	}
+	return setDeployMode(d, strings.ToLower(ent.GetDeployMode().GetValue()))
 }
 
 func cloudeosRouterStatusUpdate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
 	err := provider.AddRouter(d)
 	if err != nil {
 		return err
@@ -231,6 +320,7 @@ func cloudeosRouterStatusUpdate(d *schema.ResourceData, m interface{}) error {
 
 func cloudeosRouterStatusDelete(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutDelete))
 	err := provider.DeleteRouter(d)
 	if err != nil {
 		return err
@@ -252,3 +342,103 @@ func cloudeosRouterStatusDelete(d *schema.ResourceData, m interface{}) error {
 	d.SetId("")
 	return nil
 }
+
+func cloudeosRouterStatusImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData,
+	error) {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	// The import ID is either the tf_id or <vpc_id>/<name> of the router,
+	// where name is the Name tag of the router
+	tfID := d.Id()
+	if !strings.HasPrefix(tfID, RtrPrefix) {
+		sep := strings.LastIndex(tfID, "/")
+		if sep <= 0 || sep == len(tfID)-1 {
+			return nil, fmt.Errorf("Unexpected import ID %q, expected a tf_id or "+
+				"<vpc_id>/<name>", tfID)
+		}
+		rtr, err := provider.GetRouterByName(tfID[:sep], tfID[sep+1:])
+		if err != nil {
+			return nil, err
+		}
+		tfID = rtr.GetKey().GetId().GetValue()
+	}
+	if err := d.Set("tf_id", tfID); err != nil {
+		return nil, err
+	}
+	if err := importReplaceOnChange(d); err != nil {
+		return nil, err
+	}
+
+	resp, err := provider.GetRouterResponse(d)
+	if err != nil {
+		return nil, err
+	}
+	ent := resp.GetValue()
+	if ent.GetKey().GetId().GetValue() == "" {
+		return nil, fmt.Errorf("cloudeos_router_status %s not found in CVaaS", d.Id())
+	}
+	if err := parseRtrImport(ent, nil, d, true); err != nil {
+		return nil, err
+	}
+	if err := parseRtrCvStatus(ent, d); err != nil {
+		return nil, err
+	}
+	if err := d.Set("wait_for_ready", false); err != nil {
+		return nil, err
+	}
+
+	d.SetId("cloudeos-router-status" + strings.TrimPrefix(tfID, RtrPrefix))
+	return []*schema.ResourceData{d}, nil
+}
+
+// cloudeosRouterStatusV0 is the schema of cloudeos_router_status before the
+// intf_* lists and public_ip were replaced by interface blocks
+func cloudeosRouterStatusV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: legacyStateSchema(map[string]schema.ValueType{
+			"cloud_provider":               schema.TypeString,
+			"cv_container":                 schema.TypeString,
+			"vpc_id":                       schema.TypeString,
+			"rg_name":                      schema.TypeString,
+			"rg_location":                  schema.TypeString,
+			"instance_type":                schema.TypeString,
+			"instance_id":                  schema.TypeString,
+			"tags":                         schema.TypeMap,
+			"availability_zone":            schema.TypeString,
+			"primary_network_interface_id": schema.TypeString,
+			"availability_set_id":          schema.TypeString,
+			"public_ip":                    schema.TypeString,
+			"intf_name":                    schema.TypeList,
+			"intf_id":                      schema.TypeList,
+			"intf_private_ip":              schema.TypeList,
+			"intf_subnet_id":               schema.TypeList,
+			"intf_type":                    schema.TypeList,
+			"private_rt_table_ids":         schema.TypeList,
+			"internal_rt_table_ids":        schema.TypeList,
+			"public_rt_table_ids":          schema.TypeList,
+			"ha_name":                      schema.TypeString,
+			"cnps":                         schema.TypeString,
+			"region":                       schema.TypeString,
+			"is_rr":                        schema.TypeBool,
+			"deployment_status":            schema.TypeString,
+			"tf_id":                        schema.TypeString,
+			"routing_resource_info":        schema.TypeList,
+			"router_bgp_asn":               schema.TypeString,
+			"deploy_mode":                  schema.TypeString,
+			"replace_on_change":            schema.TypeBool,
+			"wait_for_ready":               schema.TypeBool,
+			"cv_status_code":               schema.TypeString,
+			"cv_status_desc":               schema.TypeString,
+			"cv_status_recommended_action": schema.TypeString,
+			"device_status":                schema.TypeString,
+		}),
+	}
+}
+
+// cloudeosRouterStatusStateUpgradeV0 moves the intf_* lists and public_ip to
+// interface blocks
+func cloudeosRouterStatusStateUpgradeV0(rawState map[string]interface{},
+	meta interface{}) (map[string]interface{}, error) {
+	return routerIntfStateUpgradeV0(rawState, true), nil
+}
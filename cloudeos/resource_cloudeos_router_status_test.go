@@ -0,0 +1,122 @@
+// Copyright (c) 2020 Arista Networks, Inc.
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+package cloudeos
+
+import (
+	"reflect"
+	"testing"
+
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestParseRtrCvStatus(t *testing.T) {
+	rtr := &cdv1_api.RouterConfig{
+		CvInfo: &cdv1_api.CVInfo{
+			CvStatusCode:              cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_FAILED,
+			CvStatusDesc:              wrapperspb.String("Bootstrap failed"),
+			CvStatusRecommendedAction: wrapperspb.String("Check the router logs"),
+			DeviceStatus:              cdv1_api.DeviceStatusCode_DEVICE_STATUS_CODE_ERROR,
+		},
+	}
+
+	d := cloudeosRouterStatus().TestResourceData()
+	if err := parseRtrCvStatus(rtr, d); err != nil {
+		t.Fatalf("Failed to parse the router status: %s", err)
+	}
+	for attr, want := range map[string]string{
+		"cv_status_code":               "failed",
+		"cv_status_desc":               "Bootstrap failed",
+		"cv_status_recommended_action": "Check the router logs",
+		"device_status":                "error",
+	} {
+		if got := d.Get(attr).(string); got != want {
+			t.Errorf("%s is %q; want %q", attr, got, want)
+		}
+	}
+
+	// A router without CVInfo has no status yet
+	d = cloudeosRouterStatus().TestResourceData()
+	if err := parseRtrCvStatus(&cdv1_api.RouterConfig{}, d); err != nil {
+		t.Fatalf("Failed to parse the router status: %s", err)
+	}
+	if got := d.Get("cv_status_code").(string); got != "" {
+		t.Errorf("cv_status_code is %q; want empty", got)
+	}
+}
+
+func TestGcpRouterAttributes(t *testing.T) {
+	d := cloudeosRouterStatus().TestResourceData()
+	d.Set("cloud_provider", "gcp")
+	d.Set("region", "us-central1")
+	d.Set("availability_zone", "us-central1-a")
+	if err := checkGcpZoneInRegion(d, "cloudeos_router_status"); err != nil {
+		t.Errorf("Unexpected error for a zone of the region: %s", err)
+	}
+	if err := checkUnsupportedAttributes(d, "cloudeos_router_status", "gcp",
+		[]string{"rg_name", "rg_location"}); err != nil {
+		t.Errorf("Unexpected error without Azure attributes: %s", err)
+	}
+
+	d.Set("availability_zone", "us-east1-b")
+	if err := checkGcpZoneInRegion(d, "cloudeos_router_status"); err == nil {
+		t.Error("Expected an error for a zone of another region")
+	}
+	d.Set("rg_name", "rg")
+	if err := checkUnsupportedAttributes(d, "cloudeos_router_status", "gcp",
+		[]string{"rg_name", "rg_location"}); err == nil {
+		t.Error("Expected an error for rg_name on a GCP router")
+	}
+
+	// The checks only apply to GCP
+	d.Set("cloud_provider", "azure")
+	if err := checkGcpZoneInRegion(d, "cloudeos_router_status"); err != nil {
+		t.Errorf("Unexpected error for an Azure router: %s", err)
+	}
+	if err := checkUnsupportedAttributes(d, "cloudeos_router_status", "gcp",
+		[]string{"rg_name", "rg_location"}); err != nil {
+		t.Errorf("Unexpected error for an Azure router: %s", err)
+	}
+}
+
+func TestRouterStatusStateUpgradeV0(t *testing.T) {
+	v0 := map[string]interface{}{
+		"tf_id":           "rtr-1",
+		"public_ip":       "3.3.3.3",
+		"intf_name":       []interface{}{"publicIntf", "internalIntf"},
+		"intf_id":         []interface{}{"eni-1", "eni-2"},
+		"intf_private_ip": []interface{}{"10.0.0.101", "10.0.1.101"},
+		"intf_subnet_id":  []interface{}{"subnet-1", "subnet-2"},
+		"intf_type":       []interface{}{"public", "internal"},
+	}
+	want := map[string]interface{}{
+		"tf_id": "rtr-1",
+		"interface": []interface{}{
+			map[string]interface{}{
+				"name":        "publicIntf",
+				"intf_id":     "eni-1",
+				"type":        "public",
+				"private_ips": []interface{}{"10.0.0.101"},
+				"subnet_id":   "subnet-1",
+				"public_ip":   "3.3.3.3",
+			},
+			map[string]interface{}{
+				"name":        "internalIntf",
+				"intf_id":     "eni-2",
+				"type":        "internal",
+				"private_ips": []interface{}{"10.0.1.101"},
+				"subnet_id":   "subnet-2",
+			},
+		},
+	}
+	got, err := cloudeosRouterStatusStateUpgradeV0(v0, nil)
+	if err != nil {
+		t.Fatalf("Failed to upgrade state: %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Upgraded state %v; want %v", got, want)
+	}
+}
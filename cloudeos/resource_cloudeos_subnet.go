@@ -20,6 +20,10 @@ func cloudeosSubnet() *schema.Resource {
 		Update: cloudeosSubnetUpdate,
 		Delete: cloudeosSubnetDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: cloudeosSubnetImport,
+		},
+
 		Schema: map[string]*schema.Schema{
 			"cloud_provider": {
 				Type:        schema.TypeString,
@@ -74,11 +78,15 @@ func cloudeosSubnet() *schema.Resource {
 				Type:     schema.TypeString,
 			},
 		},
+		// GCP subnets are regional
+		CustomizeDiff: unsupportedAttributes("cloudeos_subnet", "gcp", "vnet_name",
+			"availability_zone"),
 	}
 }
 
 func cloudeosSubnetCreate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
 	err := provider.AddSubnet(d)
 	if err != nil {
 		return err
@@ -101,6 +109,7 @@ func cloudeosSubnetRead(d *schema.ResourceData, m interface{}) error {
 
 func cloudeosSubnetUpdate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
 	err := provider.AddSubnet(d)
 	if err != nil {
 		return err
@@ -112,6 +121,7 @@ func cloudeosSubnetUpdate(d *schema.ResourceData, m interface{}) error {
 
 func cloudeosSubnetDelete(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutDelete))
 	err := provider.DeleteSubnet(d)
 	if err != nil {
 		return err
@@ -122,3 +132,36 @@ func cloudeosSubnetDelete(d *schema.ResourceData, m interface{}) error {
 	d.SetId("")
 	return nil
 }
+
+func cloudeosSubnetImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData,
+	error) {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	// The import ID is either the tf_id or the subnet_id of the subnet
+	tfID := d.Id()
+	if !strings.HasPrefix(tfID, SubnetPrefix) {
+		subnet, err := provider.GetSubnetBySubnetID(tfID)
+		if err != nil {
+			return nil, err
+		}
+		tfID = subnet.GetKey().GetId().GetValue()
+	}
+	if err := d.Set("tf_id", tfID); err != nil {
+		return nil, err
+	}
+
+	resp, err := provider.GetSubnetResponse(d)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetValue().GetKey().GetId().GetValue() == "" {
+		return nil, fmt.Errorf("cloudeos_subnet %s not found in CVaaS", d.Id())
+	}
+	if err := parseSubnetResponse(resp.GetValue(), d); err != nil {
+		return nil, err
+	}
+
+	d.SetId("cloudeos-subnet" + strings.TrimPrefix(tfID, SubnetPrefix))
+	return []*schema.ResourceData{d}, nil
+}
@@ -6,10 +6,12 @@ package cloudeos
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"strings"
 	"time"
 
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -22,43 +24,42 @@ func cloudeosTopology() *schema.Resource {
 		Update: cloudeosTopologyUpdate,
 		Delete: cloudeosTopologyDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: cloudeosTopologyImport,
+		},
+
 		Timeouts: &schema.ResourceTimeout{
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
 			"topology_name": {
-				Type:             schema.TypeString,
-				Required:         true,
-				Description:      "Name of the base topology",
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the base topology",
 			},
 			"bgp_asn": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				Description:      "Range, a-b, of BGP ASN’s used for topology",
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Range, a-b, of BGP ASN’s used for topology",
 			},
 			"vtep_ip_cidr": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				Description:      "CIDR block for VTEP IPs on cloudeos",
-				ValidateFunc:     validateCIDRBlock,
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "CIDR block for VTEP IPs on cloudeos",
+				ValidateFunc: validateCIDRBlock,
 			},
 			"terminattr_ip_cidr": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				Description:      "Loopback IP range on cloudeos",
-				ValidateFunc:     validateCIDRBlock,
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Loopback IP range on cloudeos",
+				ValidateFunc: validateCIDRBlock,
 			},
 			"dps_controlplane_cidr": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				Description:      "CIDR block for TerminAttr IPs on cloudeos",
-				ValidateFunc:     validateCIDRBlock,
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "CIDR block for TerminAttr IPs on cloudeos",
+				ValidateFunc: validateCIDRBlock,
 			},
 			"eos_managed": {
 				Type:        schema.TypeSet,
@@ -72,13 +73,16 @@ func cloudeosTopology() *schema.Resource {
 				Type:     schema.TypeString,
 			},
 			"deploy_mode": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				DiffSuppressFunc: suppressAttributeChange,
-				Default:          "",
-				Description:      "Deployment type of the topology - provision or empty",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Deployment type of the topology - provision or empty",
 			},
+			"replace_on_change": replaceOnChangeSchema(),
 		},
+		CustomizeDiff: immutableAttributes("cloudeos_topology",
+			"topology_name", "bgp_asn", "vtep_ip_cidr", "terminattr_ip_cidr",
+			"dps_controlplane_cidr", "deploy_mode"),
 	}
 }
 
@@ -106,6 +110,7 @@ func validateInputVarsAgainstDeployMode(d *schema.ResourceData, deployMode strin
 
 func cloudeosTopologyCreate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
 
 	deployMode := d.Get("deploy_mode").(string)
 	err := validateInputVarsAgainstDeployMode(d, deployMode)
@@ -129,11 +134,24 @@ func cloudeosTopologyCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func cloudeosTopologyRead(d *schema.ResourceData, m interface{}) error {
-	return nil
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+	topoInfo, err := provider.GetTopologyInfo(d)
+	if err != nil {
+		return err
+	}
+
+	uuid := "cloudeos-topology" + strings.TrimPrefix(d.Get("tf_id").(string), TopoPrefix)
+	if removeIfGone(d, topoInfo.GetKey().GetId().GetValue() != "", uuid) {
+		return nil
+	}
+
+	return parseTopoResponse(topoInfo, d)
 }
 
 func cloudeosTopologyUpdate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
 	err := provider.AddTopology(d)
 	if err != nil {
 		return err
@@ -146,6 +164,7 @@ func cloudeosTopologyUpdate(d *schema.ResourceData, m interface{}) error {
 
 func cloudeosTopologyDelete(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutDelete))
 	err := provider.DeleteTopology(d)
 	if err != nil {
 		return err
@@ -165,3 +184,38 @@ func cloudeosTopologyDelete(d *schema.ResourceData, m interface{}) error {
 	d.SetId("")
 	return nil
 }
+
+func cloudeosTopologyImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData,
+	error) {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	// The import ID is either the tf_id or the topology_name
+	tfID := d.Id()
+	if !strings.HasPrefix(tfID, TopoPrefix) {
+		topoInfo, err := provider.GetTopologyInfoByName(tfID, "",
+			cdv1_api.TopologyInfoType_TOPOLOGY_INFO_TYPE_META)
+		if err != nil {
+			return nil, err
+		}
+		tfID = topoInfo.GetKey().GetId().GetValue()
+	}
+	if err := d.Set("tf_id", tfID); err != nil {
+		return nil, err
+	}
+	if err := importReplaceOnChange(d); err != nil {
+		return nil, err
+	}
+
+	topoInfo, err := provider.GetTopologyInfo(d)
+	if err != nil {
+		return nil, err
+	}
+	if topoInfo.GetKey().GetId().GetValue() == "" ||
+		topoInfo.GetTopoType() != cdv1_api.TopologyInfoType_TOPOLOGY_INFO_TYPE_META {
+		return nil, fmt.Errorf("cloudeos_topology %s not found in CVaaS", d.Id())
+	}
+
+	d.SetId("cloudeos-topology" + strings.TrimPrefix(tfID, TopoPrefix))
+	return []*schema.ResourceData{d}, nil
+}
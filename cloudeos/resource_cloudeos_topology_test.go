@@ -53,8 +53,18 @@ func TestResourceTopology(t *testing.T) {
 				Check:  testResourceInitialTopologyCheck,
 			},
 			{
-				Config: testResourceUpdatedTopologyConfig,
-				Check:  testResourceUpdatedTopologyCheck,
+				Config:      testResourceUpdatedTopologyConfig,
+				ExpectError: regexp.MustCompile("Attribute bgp_asn of cloudeos_topology .* cannot be changed"),
+			},
+			{
+				Config: testResourceReplacedTopologyConfig,
+				Check:  testResourceReplacedTopologyCheck,
+			},
+			{
+				ResourceName:      "cloudeos_topology.topology2",
+				ImportState:       true,
+				ImportStateId:     "topo-test2",
+				ImportStateVerify: true,
 			},
 		},
 	})
@@ -69,7 +79,7 @@ provider "cloudeos" {
 }
 
 resource "cloudeos_topology" "topology2" {
-   topology_name = "topo-test26"
+   topology_name = "topo-test2"
    bgp_asn = "65000-65100"
    vtep_ip_cidr = "1.0.0.0/16"
    terminattr_ip_cidr = "2.0.0.0/16"
@@ -265,12 +275,30 @@ resource "cloudeos_topology" "topology2" {
 }
 `, os.Getenv("token"))
 
-func testResourceUpdatedTopologyCheck(s *terraform.State) error {
+var testResourceReplacedTopologyConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+resource "cloudeos_topology" "topology2" {
+   topology_name = "topo-test2"
+   bgp_asn = "65000-65500"
+   vtep_ip_cidr = "1.0.0.0/16"
+   terminattr_ip_cidr = "2.0.0.0/16"
+   dps_controlplane_cidr = "3.0.0.0/16"
+   replace_on_change = true
+}
+`, os.Getenv("token"))
+
+func testResourceReplacedTopologyCheck(s *terraform.State) error {
 	resourceState := s.Modules[0].Resources["cloudeos_topology.topology2"]
 	topoState := resourceState.Primary
-	if topoState.ID != resourceTopoID {
-		return fmt.Errorf("cloudeos_topology.topology ID has changed during update %s to %s",
-			resourceTopoID, topoState.ID)
+	if topoState.ID == resourceTopoID {
+		return fmt.Errorf("cloudeos_topology.topology ID has not changed during replace %s",
+			topoState.ID)
 	}
 
 	if got, want := topoState.Attributes["bgp_asn"], "65000-65500"; got != want {
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -22,11 +23,24 @@ func cloudeosVpcConfig() *schema.Resource {
 		Update: cloudeosVpcConfigUpdate,
 		Delete: cloudeosVpcConfigDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: cloudeosVpcConfigImport,
+		},
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(3 * time.Minute),
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    cloudeosVpcConfigV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: cloudeosVpcConfigStateUpgradeV0,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"cloud_provider": {
 				Type:        schema.TypeString,
@@ -42,9 +56,8 @@ func cloudeosVpcConfig() *schema.Resource {
 				},
 			},
 			"cnps": {
-				Required:         true,
-				Type:             schema.TypeString,
-				DiffSuppressFunc: suppressAttributeChange,
+				Required: true,
+				Type:     schema.TypeString,
 			},
 			"region": {
 				Required: true,
@@ -126,12 +139,6 @@ func cloudeosVpcConfig() *schema.Resource {
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
-			"peervpcidr": {
-				Type:       schema.TypeString,
-				Computed:   true,
-				Elem:       &schema.Schema{Type: schema.TypeString},
-				Deprecated: "This attribute is deprecated, use peer_vpc_cidr",
-			},
 			"peer_vpc_cidr": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -168,12 +175,18 @@ func cloudeosVpcConfig() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"replace_on_change": replaceOnChangeSchema(),
 		},
+		CustomizeDiff: customdiff.All(
+			immutableAttributes("cloudeos_vpc_config", "cnps"),
+			unsupportedAttributes("cloudeos_vpc_config", "gcp", "rg_name", "vnet_name"),
+		),
 	}
 }
 
 func cloudeosVpcConfigCreate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
 
 	var vpcDeployMode string
 
@@ -252,11 +265,27 @@ func cloudeosVpcConfigCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func cloudeosVpcConfigRead(d *schema.ResourceData, m interface{}) error {
-	return nil
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+	resp, err := provider.GetVpcResponse(d)
+	if err != nil {
+		return err
+	}
+
+	uuid := "cloudeos-vpc-config" + strings.TrimPrefix(d.Get("tf_id").(string), VpcPrefix)
+	if removeIfGone(d, resp.GetValue().GetKey().GetId().GetValue() != "", uuid) {
+		return nil
+	}
+
+	if err := parsePeerVpcInfo(resp.GetValue(), d); err != nil {
+		return err
+	}
+	return setDeployMode(d, strings.ToLower(resp.GetValue().GetDeployMode().GetValue()))
 }
 
 func cloudeosVpcConfigUpdate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
 	err := provider.AddVpcConfig(d)
 	if err != nil {
 		return err
@@ -268,6 +297,7 @@ func cloudeosVpcConfigUpdate(d *schema.ResourceData, m interface{}) error {
 
 func cloudeosVpcConfigDelete(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutDelete))
 	err := provider.DeleteVpc(d)
 	if err != nil {
 		return err
@@ -289,3 +319,77 @@ func cloudeosVpcConfigDelete(d *schema.ResourceData, m interface{}) error {
 	d.SetId("")
 	return nil
 }
+
+func cloudeosVpcConfigImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData,
+	error) {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	// The import ID is either the tf_id or the vpc_id of the VPC
+	tfID := d.Id()
+	if !strings.HasPrefix(tfID, VpcPrefix) {
+		vpc, err := provider.GetVpcByVpcID(tfID)
+		if err != nil {
+			return nil, err
+		}
+		tfID = vpc.GetKey().GetId().GetValue()
+	}
+	if err := d.Set("tf_id", tfID); err != nil {
+		return nil, err
+	}
+	if err := importReplaceOnChange(d); err != nil {
+		return nil, err
+	}
+
+	resp, err := provider.GetVpcResponse(d)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetValue().GetKey().GetId().GetValue() == "" {
+		return nil, fmt.Errorf("cloudeos_vpc_config %s not found in CVaaS", d.Id())
+	}
+	if err := parseVpcImport(resp.GetValue(), d); err != nil {
+		return nil, err
+	}
+
+	d.SetId("cloudeos-vpc-config" + strings.TrimPrefix(tfID, VpcPrefix))
+	return []*schema.ResourceData{d}, nil
+}
+
+// cloudeosVpcConfigV0 is the schema of cloudeos_vpc_config before the
+// deprecated peervpcidr was removed
+func cloudeosVpcConfigV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: legacyStateSchema(map[string]schema.ValueType{
+			"cloud_provider":    schema.TypeString,
+			"cnps":              schema.TypeString,
+			"region":            schema.TypeString,
+			"topology_name":     schema.TypeString,
+			"clos_name":         schema.TypeString,
+			"wan_name":          schema.TypeString,
+			"rg_name":           schema.TypeString,
+			"vnet_name":         schema.TypeString,
+			"role":              schema.TypeString,
+			"tags":              schema.TypeMap,
+			"topology_id":       schema.TypeString,
+			"wan_id":            schema.TypeString,
+			"clos_id":           schema.TypeString,
+			"peer_vpc_id":       schema.TypeString,
+			"peervpcidr":        schema.TypeString,
+			"peer_vpc_cidr":     schema.TypeString,
+			"peer_vnet_id":      schema.TypeString,
+			"peer_rg_name":      schema.TypeString,
+			"peer_vnet_name":    schema.TypeString,
+			"tf_id":             schema.TypeString,
+			"deploy_mode":       schema.TypeString,
+			"replace_on_change": schema.TypeBool,
+		}),
+	}
+}
+
+// cloudeosVpcConfigStateUpgradeV0 removes peervpcidr, which is replaced by
+// peer_vpc_cidr
+func cloudeosVpcConfigStateUpgradeV0(rawState map[string]interface{},
+	meta interface{}) (map[string]interface{}, error) {
+	return upgradeDeprecatedAttribute(rawState, "peervpcidr", "peer_vpc_cidr"), nil
+}
@@ -7,6 +7,7 @@ package cloudeos
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"regexp"
 	"testing"
 
@@ -234,3 +235,57 @@ func testResourceVpcConfigDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestVpcConfigStateUpgradeV0(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		v0   map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "both attributes",
+			v0: map[string]interface{}{
+				"tf_id":         "vpc-1",
+				"peer_vpc_id":   "vpc-0123456789abcdef0",
+				"peervpcidr":    "10.1.0.0/16",
+				"peer_vpc_cidr": "10.1.0.0/16",
+			},
+			want: map[string]interface{}{
+				"tf_id":         "vpc-1",
+				"peer_vpc_id":   "vpc-0123456789abcdef0",
+				"peer_vpc_cidr": "10.1.0.0/16",
+			},
+		},
+		{
+			name: "only the deprecated attribute",
+			v0: map[string]interface{}{
+				"tf_id":      "vpc-1",
+				"peervpcidr": "10.1.0.0/16",
+			},
+			want: map[string]interface{}{
+				"tf_id":         "vpc-1",
+				"peer_vpc_cidr": "10.1.0.0/16",
+			},
+		},
+		{
+			name: "no peer",
+			v0: map[string]interface{}{
+				"tf_id":         "vpc-1",
+				"peervpcidr":    "",
+				"peer_vpc_cidr": "",
+			},
+			want: map[string]interface{}{
+				"tf_id":         "vpc-1",
+				"peer_vpc_cidr": "",
+			},
+		},
+	} {
+		got, err := cloudeosVpcConfigStateUpgradeV0(tc.v0, nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to upgrade state: %s", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: Upgraded state %v; want %v", tc.name, got, tc.want)
+		}
+	}
+}
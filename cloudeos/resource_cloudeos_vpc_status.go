@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -23,6 +24,10 @@ func cloudeosVpcStatus() *schema.Resource {
 		Update: cloudeosVpcStatusUpdate,
 		Delete: cloudeosVpcStatusDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: cloudeosVpcStatusImport,
+		},
+
 		Timeouts: &schema.ResourceTimeout{
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
@@ -126,12 +131,17 @@ func cloudeosVpcStatus() *schema.Resource {
 				Description: "The unique identifier of the account",
 			},
 			"deploy_mode": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				DiffSuppressFunc: suppressAttributeChange,
-				Description:      "Deployment mode for the resources: provision or empty",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Deployment mode for the resources: provision or empty",
 			},
+			"replace_on_change": replaceOnChangeSchema(),
 		},
+		CustomizeDiff: customdiff.All(
+			immutableAttributes("cloudeos_vpc_status", "deploy_mode"),
+			unsupportedAttributes("cloudeos_vpc_status", "gcp", "rg_name", "vnet_name",
+				"resource_group"),
+		),
 	}
 }
 
@@ -142,6 +152,7 @@ func cloudeosVpcStatusCreate(d *schema.ResourceData, m interface{}) error {
 	}
 
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
 	err = provider.AddVpc(d)
 	if err != nil {
 		return err
@@ -154,11 +165,24 @@ func cloudeosVpcStatusCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func cloudeosVpcStatusRead(d *schema.ResourceData, m interface{}) error {
-	return nil
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+	resp, err := provider.GetVpcResponse(d)
+	if err != nil {
+		return err
+	}
+
+	uuid := "cloudeos-vpc-status" + strings.TrimPrefix(d.Get("tf_id").(string), VpcPrefix)
+	if removeIfGone(d, resp.GetValue().GetKey().GetId().GetValue() != "", uuid) {
+		return nil
+	}
+
+	return parseVpcStatusResponse(resp.GetValue(), d)
 }
 
 func cloudeosVpcStatusUpdate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
 	err := provider.AddVpc(d)
 	if err != nil {
 		return err
@@ -171,6 +195,7 @@ func cloudeosVpcStatusUpdate(d *schema.ResourceData, m interface{}) error {
 
 func cloudeosVpcStatusDelete(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutDelete))
 	err := provider.DeleteVpc(d)
 	if err != nil {
 		return err
@@ -192,3 +217,42 @@ func cloudeosVpcStatusDelete(d *schema.ResourceData, m interface{}) error {
 	d.SetId("")
 	return nil
 }
+
+func cloudeosVpcStatusImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData,
+	error) {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	// The import ID is either the tf_id or the vpc_id of the VPC
+	tfID := d.Id()
+	if !strings.HasPrefix(tfID, VpcPrefix) {
+		vpc, err := provider.GetVpcByVpcID(tfID)
+		if err != nil {
+			return nil, err
+		}
+		tfID = vpc.GetKey().GetId().GetValue()
+	}
+	if err := d.Set("tf_id", tfID); err != nil {
+		return nil, err
+	}
+	if err := importReplaceOnChange(d); err != nil {
+		return nil, err
+	}
+
+	resp, err := provider.GetVpcResponse(d)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetValue().GetKey().GetId().GetValue() == "" {
+		return nil, fmt.Errorf("cloudeos_vpc_status %s not found in CVaaS", d.Id())
+	}
+	if err := parseVpcImport(resp.GetValue(), d); err != nil {
+		return nil, err
+	}
+	if err := d.Set("account", resp.GetValue().GetAccount().GetValue()); err != nil {
+		return nil, err
+	}
+
+	d.SetId("cloudeos-vpc-status" + strings.TrimPrefix(tfID, VpcPrefix))
+	return []*schema.ResourceData{d}, nil
+}
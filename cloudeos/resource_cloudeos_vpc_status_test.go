@@ -10,8 +10,11 @@ import (
 	"regexp"
 	"testing"
 
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
+
 	r "github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestResourceVpcStatus(t *testing.T) {
@@ -288,3 +291,65 @@ func testResourceVpcStatusDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestGcpVpcName(t *testing.T) {
+	for _, tc := range []struct {
+		tags  map[string]interface{}
+		vpcID string
+		want  string
+	}{
+		{
+			tags:  map[string]interface{}{"Name": "gcp-edge"},
+			vpcID: "edge-network",
+			want:  "gcp-edge",
+		},
+		{
+			vpcID: "edge-network",
+			want:  "edge-network",
+		},
+		{
+			vpcID: "https://www.googleapis.com/compute/v1/projects/p/global/networks/edge-network",
+			want:  "edge-network",
+		},
+	} {
+		d := cloudeosVpcStatus().TestResourceData()
+		d.Set("cloud_provider", "gcp")
+		d.Set("vpc_id", tc.vpcID)
+		if tc.tags != nil {
+			d.Set("tags", tc.tags)
+		}
+		vpcName, cpType := getCpTypeAndVpcName(d)
+		if cpType != cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_GCP {
+			t.Errorf("Cloud provider type is %v; want %v", cpType,
+				cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_GCP)
+		}
+		if vpcName != tc.want {
+			t.Errorf("Name of %s is %q; want %q", tc.vpcID, vpcName, tc.want)
+		}
+	}
+}
+
+func TestParseGcpVpcStatusResponse(t *testing.T) {
+	d := cloudeosVpcStatus().TestResourceData()
+	d.Set("cloud_provider", "gcp")
+	d.Set("cidr_block", "10.10.0.0/16")
+	d.Set("security_group_id", "edge-firewall")
+
+	vpc := &cdv1_api.VpcConfig{
+		VpcId: wrapperspb.String("edge-network"),
+		CpT:   cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_GCP,
+	}
+	if err := parseVpcStatusResponse(vpc, d); err != nil {
+		t.Fatalf("Failed to parse the vpc: %s", err)
+	}
+	// Neither is stored in CVaaS for GCP, so the configured values are kept
+	for attr, want := range map[string]string{
+		"vpc_id":            "edge-network",
+		"cidr_block":        "10.10.0.0/16",
+		"security_group_id": "edge-firewall",
+	} {
+		if got := d.Get(attr).(string); got != want {
+			t.Errorf("%s is %q; want %q", attr, got, want)
+		}
+	}
+}
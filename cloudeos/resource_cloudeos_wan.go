@@ -6,10 +6,12 @@ package cloudeos
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"strings"
 	"time"
 
+	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -22,22 +24,24 @@ func cloudeosWan() *schema.Resource {
 		Update: cloudeosWanUpdate,
 		Delete: cloudeosWanDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: cloudeosWanImport,
+		},
+
 		Timeouts: &schema.ResourceTimeout{
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:             schema.TypeString,
-				Required:         true,
-				Description:      "Wan fabric name",
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Wan fabric name",
 			},
 			"topology_name": {
-				Type:             schema.TypeString,
-				Required:         true,
-				Description:      "Base topology name",
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Base topology name",
 			},
 			"edge_to_edge_peering": {
 				Type:     schema.TypeBool,
@@ -55,22 +59,25 @@ func cloudeosWan() *schema.Resource {
 				Default:  true,
 			},
 			"cv_container_name": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				Description:      "Container name for edge",
-				Default:          "CloudEdge",
-				DiffSuppressFunc: suppressAttributeChange,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Container name for edge",
+				Default:     "CloudEdge",
 			},
 			"tf_id": {
 				Computed: true,
 				Type:     schema.TypeString,
 			},
+			"replace_on_change": replaceOnChangeSchema(),
 		},
+		CustomizeDiff: immutableAttributes("cloudeos_wan",
+			"name", "topology_name", "cv_container_name"),
 	}
 }
 
 func cloudeosWanCreate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutCreate))
 	allowed, err := provider.IsValidTopoAddition(d, "TOPO_INFO_WAN")
 	if !allowed || err != nil {
 		return err
@@ -88,11 +95,24 @@ func cloudeosWanCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func cloudeosWanRead(d *schema.ResourceData, m interface{}) error {
-	return nil
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+	topoInfo, err := provider.GetTopologyInfo(d)
+	if err != nil {
+		return err
+	}
+
+	uuid := "cloudeos-wan" + strings.TrimPrefix(d.Get("tf_id").(string), WanPrefix)
+	if removeIfGone(d, topoInfo.GetKey().GetId().GetValue() != "", uuid) {
+		return nil
+	}
+
+	return parseWanResponse(topoInfo, d)
 }
 
 func cloudeosWanUpdate(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutUpdate))
 	err := provider.AddWanTopology(d)
 	if err != nil {
 		return err
@@ -105,6 +125,7 @@ func cloudeosWanUpdate(d *schema.ResourceData, m interface{}) error {
 
 func cloudeosWanDelete(d *schema.ResourceData, m interface{}) error {
 	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutDelete))
 	err := provider.DeleteWanTopology(d)
 	if err != nil {
 		return err
@@ -126,3 +147,42 @@ func cloudeosWanDelete(d *schema.ResourceData, m interface{}) error {
 	d.SetId("")
 	return nil
 }
+
+func cloudeosWanImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData,
+	error) {
+	provider := m.(CloudeosProvider)
+	provider.setTimeout(d.Timeout(schema.TimeoutRead))
+
+	// The import ID is either the tf_id or <topology_name>/<name>
+	tfID := d.Id()
+	if !strings.HasPrefix(tfID, WanPrefix) {
+		parts, err := splitImportID(tfID, 2, "<topology_name>/<name>")
+		if err != nil {
+			return nil, err
+		}
+		topoInfo, err := provider.GetTopologyInfoByName(parts[0], parts[1],
+			cdv1_api.TopologyInfoType_TOPOLOGY_INFO_TYPE_WAN)
+		if err != nil {
+			return nil, err
+		}
+		tfID = topoInfo.GetKey().GetId().GetValue()
+	}
+	if err := d.Set("tf_id", tfID); err != nil {
+		return nil, err
+	}
+	if err := importReplaceOnChange(d); err != nil {
+		return nil, err
+	}
+
+	topoInfo, err := provider.GetTopologyInfo(d)
+	if err != nil {
+		return nil, err
+	}
+	if topoInfo.GetKey().GetId().GetValue() == "" ||
+		topoInfo.GetTopoType() != cdv1_api.TopologyInfoType_TOPOLOGY_INFO_TYPE_WAN {
+		return nil, fmt.Errorf("cloudeos_wan %s not found in CVaaS", d.Id())
+	}
+
+	d.SetId("cloudeos-wan" + strings.TrimPrefix(tfID, WanPrefix))
+	return []*schema.ResourceData{d}, nil
+}
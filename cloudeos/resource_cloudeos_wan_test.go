@@ -29,8 +29,18 @@ func TestResourceWan(t *testing.T) {
 				ExpectError: regexp.MustCompile("cloudeos_wan wan-test3 already exists"),
 			},
 			{
-				Config: testResourceUpdatedWanConfig,
-				Check:  testResourceUpdatedWanCheck,
+				Config:      testResourceUpdatedWanConfig,
+				ExpectError: regexp.MustCompile("Attribute name of cloudeos_wan .* cannot be changed"),
+			},
+			{
+				Config: testResourceReplacedWanConfig,
+				Check:  testResourceReplacedWanCheck,
+			},
+			{
+				ResourceName:      "cloudeos_wan.wan",
+				ImportState:       true,
+				ImportStateId:     "topo-test2/wan-test-update2",
+				ImportStateVerify: true,
 			},
 		},
 	})
@@ -144,7 +154,32 @@ resource "cloudeos_wan" "wan" {
 }
 `, os.Getenv("token"))
 
-func testResourceUpdatedWanCheck(s *terraform.State) error {
+var testResourceReplacedWanConfig = fmt.Sprintf(`
+provider "cloudeos" {
+  cvaas_domain = "apiserver.cv-play.corp.arista.io"
+  cvaas_server = "www.cv-play.corp.arista.io"
+  // clouddeploy token
+  service_account_web_token = %q
+}
+
+resource "cloudeos_topology" "topology" {
+   topology_name = "topo-test2"
+   bgp_asn = "65000-65100"
+   vtep_ip_cidr = "1.0.0.0/16"
+   terminattr_ip_cidr = "2.0.0.0/16"
+   dps_controlplane_cidr = "3.0.0.0/16"
+   replace_on_change = true
+}
+
+resource "cloudeos_wan" "wan" {
+   name = "wan-test-update2"
+   topology_name = cloudeos_topology.topology.topology_name
+   cv_container_name = "CloudEdge"
+   replace_on_change = true
+}
+`, os.Getenv("token"))
+
+func testResourceReplacedWanCheck(s *terraform.State) error {
 	resourceState := s.Modules[0].Resources["cloudeos_wan.wan"]
 	if resourceState == nil {
 		return fmt.Errorf("cloudeos_wan.wan resource not found")
@@ -155,8 +190,8 @@ func testResourceUpdatedWanCheck(s *terraform.State) error {
 		return fmt.Errorf("cloudeos_wan.wan resource has no primary instance")
 	}
 
-	if instanceState.ID != wanResourceID {
-		return fmt.Errorf("cloudeos_wan.wan ID has changed %s", instanceState.ID)
+	if instanceState.ID == wanResourceID {
+		return fmt.Errorf("cloudeos_wan.wan ID has not changed %s", instanceState.ID)
 	}
 
 	if got, want := instanceState.Attributes["name"], "wan-test-update2"; got != want {
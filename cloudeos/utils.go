@@ -1,12 +1,15 @@
 package cloudeos
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	cdv1_api "github.com/aristanetworks/terraform-provider-cloudeos/cloudeos/arista/clouddeploy.v1"
 
@@ -30,6 +33,18 @@ func validateDeployModeWithRole(d *schema.ResourceData) error {
 	return nil
 }
 
+// removeIfGone removes the resource from the state when it isn't found in
+// CVaaS, i.e. it was deleted outside of terraform, so that it gets recreated.
+// It returns whether the resource was removed.
+func removeIfGone(d *schema.ResourceData, found bool, uuid string) bool {
+	if found {
+		return false
+	}
+	log.Print("[WARN] " + uuid + " not found in CVaaS, removing from state")
+	d.SetId("")
+	return true
+}
+
 func getCloudProviderType(d *schema.ResourceData) cdv1_api.CloudProviderType {
 	cloudProvider := d.Get("cloud_provider").(string)
 	cpType := cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_UNSPECIFIED
@@ -38,6 +53,8 @@ func getCloudProviderType(d *schema.ResourceData) cdv1_api.CloudProviderType {
 		cpType = cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_AWS
 	case strings.EqualFold("azure", cloudProvider):
 		cpType = cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_AZURE
+	case strings.EqualFold("gcp", cloudProvider):
+		cpType = cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_GCP
 	}
 	return cpType
 }
@@ -69,10 +86,38 @@ func getCpTypeAndVpcName(d *schema.ResourceData) (string, cdv1_api.CloudProvider
 	case strings.EqualFold("azure", cloudProvider):
 		cpType = cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_AZURE
 		vpcName = d.Get("vnet_name").(string)
+	case strings.EqualFold("gcp", cloudProvider):
+		cpType = cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_GCP
+		vpcName = getGcpNetworkName(d)
 	}
 	return vpcName, cpType
 }
 
+// getGcpNetworkName returns the name of a GCP VPC network. It's taken from the
+// Name tag, like in AWS, and defaults to the last part of the vpc_id, which is
+// either the network name or its self link.
+func getGcpNetworkName(d *schema.ResourceData) string {
+	if name, _ := getAwsVpcName(d); name != "" {
+		return name
+	}
+	if vpcID, ok := d.GetOk("vpc_id"); ok {
+		parts := strings.Split(strings.TrimSuffix(vpcID.(string), "/"), "/")
+		return parts[len(parts)-1]
+	}
+	return ""
+}
+
+// validateGcpZone checks that a GCP zone, e.g us-central1-a, is in the region
+func validateGcpZone(zone, region string) error {
+	if zone == "" || region == "" {
+		return nil
+	}
+	if !strings.HasPrefix(zone, region+"-") {
+		return fmt.Errorf("GCP zone %q isn't in region %q", zone, region)
+	}
+	return nil
+}
+
 func getRoleType(role string) cdv1_api.RoleType {
 	var roleType cdv1_api.RoleType
 	switch {
@@ -199,16 +244,25 @@ func setBootStrapCfg(d *schema.ResourceData, cfg string) error {
 }
 
 func parseRtrResponse(ent *cdv1_api.RouterConfig, d *schema.ResourceData) error {
-	// Parse the bootstrap_cfg, haRtrId, peerRtTable  from response and set
+	// Parse the bootstrap_cfg from response and set in schema
+	bootstrapCfg := ent.GetCvInfo().GetBootstrapCfg().GetValue()
+
+	// set bootstrap_cfg
+	if err := setBootStrapCfg(d, bootstrapCfg); err != nil {
+		return err
+	}
+	return parseRtrCvInfo(ent, d)
+}
+
+func parseRtrCvInfo(ent *cdv1_api.RouterConfig, d *schema.ResourceData) error {
+	// Parse the haRtrId and route tables set by CVaaS from response and set
 	// in schema
-	var bootstrapCfg string
 	var haRtrID string
 	var peerRtTblID []string // Internal peer route table ID
 	var publicRtTblID []string
 	var privateRtTblID []string
 	var internalRtTblID []string
 
-	bootstrapCfg = ent.GetCvInfo().GetBootstrapCfg().GetValue()
 	haRtrID = ent.GetCvInfo().GetHaRtrId().GetValue()
 	for _, id := range ent.GetCvInfo().GetPeerVpcRtTableId().GetValues() {
 		peerRtTblID = append(peerRtTblID, id)
@@ -225,16 +279,9 @@ func parseRtrResponse(ent *cdv1_api.RouterConfig, d *schema.ResourceData) error
 		privateRtTblID = append(privateRtTblID, id)
 	}
 
-	// set bootstrap_cfg
-	if err := setBootStrapCfg(d, bootstrapCfg); err != nil {
-		return err
-	}
 	if err := d.Set("ha_rtr_id", haRtrID); err != nil {
 		return fmt.Errorf("Not able to set ha_rtr_id: %v", err)
 	}
-	if err := d.Set("peerroutetableid1", peerRtTblID); err != nil {
-		return fmt.Errorf("Not able to set peer route table ID: %v ", err)
-	}
 	if err := d.Set("peer_routetable_id", peerRtTblID); err != nil {
 		return fmt.Errorf("Not able to set peer route table ID: %v ", err)
 	}
@@ -249,3 +296,740 @@ func parseRtrResponse(ent *cdv1_api.RouterConfig, d *schema.ResourceData) error
 	}
 	return nil
 }
+
+func getFabricName(fabric cdv1_api.FabricType) string {
+	switch fabric {
+	case cdv1_api.FabricType_FABRIC_TYPE_FULL_MESH:
+		return "full_mesh"
+	case cdv1_api.FabricType_FABRIC_TYPE_HUB_SPOKE:
+		return "hub_spoke"
+	}
+	return ""
+}
+
+// setDeployMode sets deploy_mode unless it only differs in case from the
+// value already in the resource, as CVaaS stores it in lower case.
+func setDeployMode(d *schema.ResourceData, deployMode string) error {
+	if strings.EqualFold(d.Get("deploy_mode").(string), deployMode) {
+		return nil
+	}
+	if err := d.Set("deploy_mode", deployMode); err != nil {
+		return fmt.Errorf("Not able to set deploy_mode: %v", err)
+	}
+	return nil
+}
+
+func parseTopoResponse(ent *cdv1_api.TopologyInfoConfig, d *schema.ResourceData) error {
+	// bgp_asn isn't set for topologies with deploy_mode provision
+	var bgpAsn string
+	asnLow := ent.GetBgpAsnLow().GetValue()
+	asnHigh := ent.GetBgpAsnHigh().GetValue()
+	if asnLow != 0 || asnHigh != 0 {
+		bgpAsn = fmt.Sprintf("%d-%d", asnLow, asnHigh)
+	}
+
+	if err := d.Set("topology_name", ent.GetName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set topology_name: %v", err)
+	}
+	if err := d.Set("bgp_asn", bgpAsn); err != nil {
+		return fmt.Errorf("Not able to set bgp_asn: %v", err)
+	}
+	if err := d.Set("vtep_ip_cidr", ent.GetVtepIpCidr().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set vtep_ip_cidr: %v", err)
+	}
+	if err := d.Set("terminattr_ip_cidr", ent.GetTerminattrIpCidr().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set terminattr_ip_cidr: %v", err)
+	}
+	if err := d.Set("dps_controlplane_cidr", ent.GetDpsControlPlaneCidr().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set dps_controlplane_cidr: %v", err)
+	}
+	if err := d.Set("eos_managed", ent.GetManagedDevices().GetValues()); err != nil {
+		return fmt.Errorf("Not able to set eos_managed: %v", err)
+	}
+	return setDeployMode(d, ent.GetDeployMode().GetValue())
+}
+
+func parseWanResponse(ent *cdv1_api.TopologyInfoConfig, d *schema.ResourceData) error {
+	wanInfo := ent.GetWanInfo()
+	if err := d.Set("topology_name", ent.GetName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set topology_name: %v", err)
+	}
+	if err := d.Set("name", wanInfo.GetWanName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set name: %v", err)
+	}
+	if err := d.Set("edge_to_edge_peering", wanInfo.GetEdgeEdgePeering().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set edge_to_edge_peering: %v", err)
+	}
+	if err := d.Set("edge_to_edge_igw", wanInfo.GetEdgeEdgeIgw().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set edge_to_edge_igw: %v", err)
+	}
+	if err := d.Set("edge_to_edge_dedicated_connect",
+		wanInfo.GetEdgeDedicatedConnect().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set edge_to_edge_dedicated_connect: %v", err)
+	}
+	if err := d.Set("cv_container_name", wanInfo.GetCvpContainerName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set cv_container_name: %v", err)
+	}
+	return nil
+}
+
+func parseClosResponse(ent *cdv1_api.TopologyInfoConfig, d *schema.ResourceData) error {
+	closInfo := ent.GetClosInfo()
+	if err := d.Set("topology_name", ent.GetName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set topology_name: %v", err)
+	}
+	if err := d.Set("name", closInfo.GetClosName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set name: %v", err)
+	}
+	if err := d.Set("fabric", getFabricName(closInfo.GetFabric())); err != nil {
+		return fmt.Errorf("Not able to set fabric: %v", err)
+	}
+	if err := d.Set("leaf_to_edge_peering", closInfo.GetLeafEdgePeering().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set leaf_to_edge_peering: %v", err)
+	}
+	if err := d.Set("leaf_to_edge_igw", closInfo.GetLeafEdgeIgw().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set leaf_to_edge_igw: %v", err)
+	}
+	if err := d.Set("leaf_encryption", closInfo.GetLeafEncryption().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set leaf_encryption: %v", err)
+	}
+	if err := d.Set("cv_container_name", closInfo.GetCvpContainerName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set cv_container_name: %v", err)
+	}
+	return nil
+}
+
+func getIntfTypeName(intfType cdv1_api.NetworkInterfaceType) string {
+	switch intfType {
+	case cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_PUBLIC:
+		return "public"
+	case cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_PRIVATE:
+		return "private"
+	case cdv1_api.NetworkInterfaceType_NETWORK_INTERFACE_TYPE_INTERNAL:
+		return "internal"
+	}
+	return ""
+}
+
+// parseRtrIntfResponse sets the interface blocks of a router resource from the
+// interfaces in the response. intf_id is only part of the
+// cloudeos_router_status schema.
+func parseRtrIntfResponse(ent *cdv1_api.RouterConfig, d *schema.ResourceData,
+	isRtrStatus bool) error {
+	// type is matched case insensitively when creating the router, so keep
+	// the configured value if it only differs in case
+	oldIntfs := d.Get("interface").([]interface{})
+	var intfs []interface{}
+	for i, intf := range ent.GetIntf().GetValues() {
+		intfType := getIntfTypeName(intf.GetIntfType())
+		if i < len(oldIntfs) {
+			oldIntfType := oldIntfs[i].(map[string]interface{})["type"].(string)
+			if strings.EqualFold(oldIntfType, intfType) {
+				intfType = oldIntfType
+			}
+		}
+		intfMap := map[string]interface{}{
+			"name":           intf.GetName().GetValue(),
+			"type":           intfType,
+			"private_ips":    intf.GetPrivateIpAddr().GetValues(),
+			"public_ip":      intf.GetPublicIpAddr().GetValue(),
+			"subnet_id":      intf.GetSubnet().GetValue(),
+			"security_group": intf.GetSecurityGroup().GetValue(),
+		}
+		if isRtrStatus {
+			intfMap["intf_id"] = intf.GetIntfId().GetValue()
+		}
+		intfs = append(intfs, intfMap)
+	}
+
+	if err := d.Set("interface", intfs); err != nil {
+		return fmt.Errorf("Not able to set interface: %v", err)
+	}
+	return nil
+}
+
+func parsePeerVpcInfo(ent *cdv1_api.VpcConfig, d *schema.ResourceData) error {
+	peerVpcInfo := ent.GetPeerVpcInfo()
+	if peerVpcInfo == nil {
+		return nil
+	}
+
+	if err := d.Set("peer_rg_name", peerVpcInfo.GetPeerRgName().GetValue()); err != nil {
+		return err
+	}
+
+	if err := d.Set("peer_vnet_name", peerVpcInfo.GetPeerVnetName().GetValue()); err != nil {
+		return err
+	}
+
+	if err := d.Set("peer_vnet_id", peerVpcInfo.GetPeerVnetId().GetValue()); err != nil {
+		return err
+	}
+
+	peerVpcCidrInfoMap := peerVpcInfo.GetPeerVpcCidr().GetValues()
+	for k := range peerVpcCidrInfoMap {
+		if err := d.Set("peer_vpc_id", k); err != nil {
+			return err
+		}
+
+		if err := d.Set("peer_vpc_cidr", peerVpcCidrInfoMap[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseVpcStatusResponse(ent *cdv1_api.VpcConfig, d *schema.ResourceData) error {
+	var cidr string
+	var securityGroups []string
+	isGcp := false
+	switch ent.GetCpT() {
+	case cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_AWS:
+		cidr = ent.GetAwsVpcInfo().GetCidr().GetValue()
+		securityGroups = ent.GetAwsVpcInfo().GetSecurityGroup().GetValues()
+	case cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_AZURE:
+		cidr = ent.GetAzVnetInfo().GetCidr().GetValue()
+		securityGroups = ent.GetAzVnetInfo().GetNsg().GetValues()
+	case cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_GCP:
+		// The cidr and security group of GCP networks aren't stored in
+		// CVaaS, keep the configured ones
+		isGcp = true
+	}
+
+	var securityGroup string
+	if len(securityGroups) > 0 {
+		securityGroup = securityGroups[0]
+	}
+
+	if err := d.Set("vpc_id", ent.GetVpcId().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set vpc_id: %v", err)
+	}
+	if !isGcp {
+		if err := d.Set("cidr_block", cidr); err != nil {
+			return fmt.Errorf("Not able to set cidr_block: %v", err)
+		}
+		if err := d.Set("security_group_id", securityGroup); err != nil {
+			return fmt.Errorf("Not able to set security_group_id: %v", err)
+		}
+	}
+	return setDeployMode(d, strings.ToLower(ent.GetDeployMode().GetValue()))
+}
+
+func getCloudProviderName(cpType cdv1_api.CloudProviderType) string {
+	switch cpType {
+	case cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_AWS:
+		return "aws"
+	case cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_AZURE:
+		return "azure"
+	case cdv1_api.CloudProviderType_CLOUD_PROVIDER_TYPE_GCP:
+		return "gcp"
+	}
+	return ""
+}
+
+func getRoleName(roleType cdv1_api.RoleType) string {
+	switch roleType {
+	case cdv1_api.RoleType_ROLE_TYPE_EDGE:
+		return "CloudEdge"
+	case cdv1_api.RoleType_ROLE_TYPE_SPINE:
+		return "CloudSpine"
+	case cdv1_api.RoleType_ROLE_TYPE_LEAF:
+		return "CloudLeaf"
+	}
+	return ""
+}
+
+func getCvStatusName(code cdv1_api.CVStatusCode) string {
+	switch code {
+	case cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_CREATED:
+		return "created"
+	case cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_DISCOVERED:
+		return "discovered"
+	case cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_PROVISIONED:
+		return "provisioned"
+	case cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_CONFIG_WIP:
+		return "config_wip"
+	case cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_READY:
+		return "ready"
+	case cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_FAILED:
+		return "failed"
+	case cdv1_api.CVStatusCode_CV_STATUS_CODE_RTR_INACTIVE:
+		return "inactive"
+	}
+	return ""
+}
+
+func getDeviceStatusName(code cdv1_api.DeviceStatusCode) string {
+	switch code {
+	case cdv1_api.DeviceStatusCode_DEVICE_STATUS_CODE_WORK_IN_PROGRESS:
+		return "work_in_progress"
+	case cdv1_api.DeviceStatusCode_DEVICE_STATUS_CODE_SUCCESS:
+		return "success"
+	case cdv1_api.DeviceStatusCode_DEVICE_STATUS_CODE_ERROR:
+		return "error"
+	}
+	return ""
+}
+
+// parseRtrCvStatus sets the CVaaS status of the router
+func parseRtrCvStatus(ent *cdv1_api.RouterConfig, d *schema.ResourceData) error {
+	cvInfo := ent.GetCvInfo()
+	attrs := map[string]interface{}{
+		"cv_status_code":               getCvStatusName(cvInfo.GetCvStatusCode()),
+		"cv_status_desc":               cvInfo.GetCvStatusDesc().GetValue(),
+		"cv_status_recommended_action": cvInfo.GetCvStatusRecommendedAction().GetValue(),
+		"device_status":                getDeviceStatusName(cvInfo.GetDeviceStatus()),
+	}
+	for attr, val := range attrs {
+		if err := d.Set(attr, val); err != nil {
+			return fmt.Errorf("Not able to set %s: %v", attr, err)
+		}
+	}
+	return nil
+}
+
+// splitImportID splits an import ID of the form <part1>/<part2>/.. into
+// exactly count parts. format is used in the error message.
+func splitImportID(id string, count int, format string) ([]string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != count {
+		return nil, fmt.Errorf("Unexpected import ID %q, expected a tf_id or %s", id, format)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("Unexpected import ID %q, expected a tf_id or %s", id, format)
+		}
+	}
+	return parts, nil
+}
+
+// parseVpcImport sets the attributes shared by cloudeos_vpc_config and
+// cloudeos_vpc_status which aren't refreshed on Read.
+func parseVpcImport(ent *cdv1_api.VpcConfig, d *schema.ResourceData) error {
+	cloudProvider := getCloudProviderName(ent.GetCpT())
+	if err := d.Set("cloud_provider", cloudProvider); err != nil {
+		return fmt.Errorf("Not able to set cloud_provider: %v", err)
+	}
+	if err := d.Set("cnps", ent.GetCnps().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set cnps: %v", err)
+	}
+	if err := d.Set("region", ent.GetRegion().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set region: %v", err)
+	}
+	if err := d.Set("role", getRoleName(ent.GetRoleType())); err != nil {
+		return fmt.Errorf("Not able to set role: %v", err)
+	}
+	if err := d.Set("topology_name", ent.GetTopologyName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set topology_name: %v", err)
+	}
+	if err := d.Set("clos_name", ent.GetClosName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set clos_name: %v", err)
+	}
+	if err := d.Set("wan_name", ent.GetWanName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set wan_name: %v", err)
+	}
+
+	// The vpc name is taken from the Name tag in AWS and GCP and from vnet_name
+	// in Azure. Other tags aren't stored in CVaaS.
+	switch cloudProvider {
+	case "aws", "gcp":
+		tags := map[string]interface{}{"Name": ent.GetName().GetValue()}
+		if err := d.Set("tags", tags); err != nil {
+			return fmt.Errorf("Not able to set tags: %v", err)
+		}
+	case "azure":
+		if err := d.Set("vnet_name", ent.GetName().GetValue()); err != nil {
+			return fmt.Errorf("Not able to set vnet_name: %v", err)
+		}
+		if err := d.Set("rg_name", ent.GetAzVnetInfo().GetResourceGroup().GetValue()); err != nil {
+			return fmt.Errorf("Not able to set rg_name: %v", err)
+		}
+	}
+	return setDeployMode(d, strings.ToLower(ent.GetDeployMode().GetValue()))
+}
+
+// parseRtrImport sets the attributes of cloudeos_router_config and
+// cloudeos_router_status which aren't refreshed on Read. vpc is the vpc
+// entry the router is deployed in.
+func parseRtrImport(ent *cdv1_api.RouterConfig, vpc *cdv1_api.VpcConfig,
+	d *schema.ResourceData, isRtrStatus bool) error {
+	cloudProvider := getCloudProviderName(ent.GetCpT())
+	if err := d.Set("cloud_provider", cloudProvider); err != nil {
+		return fmt.Errorf("Not able to set cloud_provider: %v", err)
+	}
+	if err := d.Set("cnps", ent.GetCnps().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set cnps: %v", err)
+	}
+	if err := d.Set("region", ent.GetRegion().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set region: %v", err)
+	}
+	if err := d.Set("vpc_id", ent.GetVpcId().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set vpc_id: %v", err)
+	}
+	if err := d.Set("is_rr", ent.GetRouteReflector().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set is_rr: %v", err)
+	}
+	tags := map[string]interface{}{"Name": ent.GetName().GetValue()}
+	if err := d.Set("tags", tags); err != nil {
+		return fmt.Errorf("Not able to set tags: %v", err)
+	}
+
+	var availZone string
+	switch cloudProvider {
+	case "aws":
+		availZone = ent.GetAwsRtrDetail().GetAvailZone().GetValue()
+	case "azure":
+		availZone = ent.GetAzRtrDetail().GetAvailZone().GetValue()
+	}
+
+	if !isRtrStatus {
+		if err := d.Set("topology_name", vpc.GetTopologyName().GetValue()); err != nil {
+			return fmt.Errorf("Not able to set topology_name: %v", err)
+		}
+		if err := d.Set("role", getRoleName(vpc.GetRoleType())); err != nil {
+			return fmt.Errorf("Not able to set role: %v", err)
+		}
+		if cloudProvider == "aws" {
+			if err := d.Set("availability_zone", availZone); err != nil {
+				return fmt.Errorf("Not able to set availability_zone: %v", err)
+			}
+		}
+		// The licensing model isn't stored in CVaaS, assume the default
+		if err := d.Set("cloudeos_image_offer", "cloudeos-router-payg"); err != nil {
+			return fmt.Errorf("Not able to set cloudeos_image_offer: %v", err)
+		}
+		return nil
+	}
+
+	var instanceType string
+	switch cloudProvider {
+	case "aws":
+		instanceType = ent.GetAwsRtrDetail().GetInstanceType().GetValue()
+		if err := d.Set("availability_zone", availZone); err != nil {
+			return fmt.Errorf("Not able to set availability_zone: %v", err)
+		}
+	case "azure":
+		instanceType = ent.GetAzRtrDetail().GetInstanceType().GetValue()
+		if err := d.Set("rg_location", availZone); err != nil {
+			return fmt.Errorf("Not able to set rg_location: %v", err)
+		}
+		if err := d.Set("rg_name", ent.GetAzRtrDetail().GetResGroup().GetValue()); err != nil {
+			return fmt.Errorf("Not able to set rg_name: %v", err)
+		}
+	}
+	if err := d.Set("instance_type", instanceType); err != nil {
+		return fmt.Errorf("Not able to set instance_type: %v", err)
+	}
+	if err := d.Set("instance_id", ent.GetInstanceId().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set instance_id: %v", err)
+	}
+	if err := d.Set("ha_name", ent.GetHaName().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set ha_name: %v", err)
+	}
+	rtTableIds := ent.GetRtTableIds()
+	if err := d.Set("public_rt_table_ids", rtTableIds.GetPublic().GetValues()); err != nil {
+		return fmt.Errorf("Not able to set public_rt_table_ids: %v", err)
+	}
+	if err := d.Set("private_rt_table_ids", rtTableIds.GetPrivate().GetValues()); err != nil {
+		return fmt.Errorf("Not able to set private_rt_table_ids: %v", err)
+	}
+	if err := d.Set("internal_rt_table_ids", rtTableIds.GetInternal().GetValues()); err != nil {
+		return fmt.Errorf("Not able to set internal_rt_table_ids: %v", err)
+	}
+	return nil
+}
+
+func parseSubnetResponse(ent *cdv1_api.SubnetConfig, d *schema.ResourceData) error {
+	if err := d.Set("cloud_provider", getCloudProviderName(ent.GetCpT())); err != nil {
+		return fmt.Errorf("Not able to set cloud_provider: %v", err)
+	}
+	if err := d.Set("vpc_id", ent.GetVpcId().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set vpc_id: %v", err)
+	}
+	if err := d.Set("availability_zone", ent.GetAvailZone().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set availability_zone: %v", err)
+	}
+	if err := d.Set("subnet_id", ent.GetSubnetId().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set subnet_id: %v", err)
+	}
+	if err := d.Set("computed_subnet_id", ent.GetSubnetId().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set computed_subnet_id: %v", err)
+	}
+	if err := d.Set("cidr_block", ent.GetCidr().GetValue()); err != nil {
+		return fmt.Errorf("Not able to set cidr_block: %v", err)
+	}
+	return nil
+}
+
+// parseAwsVpnResponse sets the attributes of cloudeos_aws_vpn. The state of
+// the tunnels is taken from att, the TGW attachment of the VPN connection,
+// and is empty when att is nil.
+func parseAwsVpnResponse(ent *cdv1_api.AWSVpnConfig, att *cdv1_api.AWSTgwAttachment,
+	d *schema.ResourceData) error {
+	// The attributes derived from the TGW attachment stay unset while CVaaS has
+	// the values of the attachment, or while the attachment isn't known
+	attachmentID := ent.GetVpnTgwAttachmentId().GetValue()
+	refresh := func(key, value, derived string) string {
+		if attachmentID != "" && d.Get(key).(string) == "" && (att == nil || value == derived) {
+			return ""
+		}
+		return value
+	}
+	derivedTunnels := awsVpnAttachmentTunnels(att)
+
+	tgwID := refresh("tgw_id", ent.GetTgwId().GetValue(), att.GetTgwId().GetValue())
+	vpnConnectionID := refresh("vpn_connection_id", ent.GetVpnConnectionId().GetValue(),
+		att.GetResourceId().GetValue())
+
+	attrs := map[string]string{
+		"cnps":                  ent.GetCnps().GetValue(),
+		"tgw_id":                tgwID,
+		"router_id":             ent.GetCloudeosRouterId().GetValue(),
+		"vpn_gateway_id":        ent.GetVpnGatewayId().GetValue(),
+		"vpn_connection_id":     vpnConnectionID,
+		"vpn_tgw_attachment_id": attachmentID,
+		"cgw_id":                ent.GetCgwId().GetValue(),
+		"vpc_id":                ent.GetCloudeosVpcId().GetValue(),
+	}
+	tunnelStates := []string{att.GetTun1State().GetValue(), att.GetTun2State().GetValue()}
+	var tunnels []interface{}
+	for i, tunnel := range ent.GetTunnelInfoList().GetValues() {
+		var state string
+		if i < len(tunnelStates) {
+			state = tunnelStates[i]
+		}
+		values := map[string]interface{}{
+			"bgp_asn":       tunnel.GetTunnelBgpAsn().GetValue(),
+			"bgp_holdtime":  tunnel.GetTunnelBgpHoldtime().GetValue(),
+			"preshared_key": hashSensitiveValue(tunnel.GetTunnelPresharedKey().GetValue()),
+			"ipsec":         parseIpsecInfo(tunnel.GetIpsecInfo()),
+			"state":         state,
+		}
+		for attr, value := range map[string]string{
+			"aws_endpoint_ip":   tunnel.GetTunnelAwsEndpointIp().GetValue(),
+			"router_overlay_ip": tunnel.GetTunnelRouterOverlayIp().GetValue(),
+			"aws_overlay_ip":    tunnel.GetTunnelAwsOverlayIp().GetValue(),
+		} {
+			var derived string
+			if i < len(derivedTunnels) {
+				derived = derivedTunnels[i][attr]
+			}
+			values[attr] = refresh(fmt.Sprintf("tunnel.%d.%s", i, attr), value, derived)
+		}
+		tunnels = append(tunnels, values)
+	}
+	if err := d.Set("tunnel", tunnels); err != nil {
+		return fmt.Errorf("Not able to set tunnel: %v", err)
+	}
+
+	for attr, value := range attrs {
+		if err := d.Set(attr, value); err != nil {
+			return fmt.Errorf("Not able to set %s: %v", attr, err)
+		}
+	}
+	return nil
+}
+
+// parseIpsecInfo returns the ipsec block of a tunnel, which is empty when
+// the tunnel uses the AWS defaults
+func parseIpsecInfo(info *cdv1_api.IpsecInfo) []interface{} {
+	intValue := func(value string) int {
+		i, _ := strconv.Atoi(value)
+		return i
+	}
+	ipsec := map[string]interface{}{
+		"ike_integrity":    info.GetIkeIntegrity().GetValue(),
+		"ike_encryption":   info.GetIkeEncryption().GetValue(),
+		"ike_lifetime":     intValue(info.GetIkeLifetime().GetValue()),
+		"ike_pfs":          intValue(info.GetIkePfs().GetValue()),
+		"ipsec_integrity":  info.GetIpsecIntegrity().GetValue(),
+		"ipsec_encryption": info.GetIpsecEncryption().GetValue(),
+		"ipsec_pfs":        intValue(info.GetIpsecPfs().GetValue()),
+		"ipsec_mode":       info.GetIpsecMode().GetValue(),
+		"dpd_interval":     intValue(info.GetDpdInterval().GetValue()),
+		"dpd_retries":      intValue(info.GetDpdRetries().GetValue()),
+	}
+	for _, value := range ipsec {
+		if !isZeroValue(value) {
+			return []interface{}{ipsec}
+		}
+	}
+	return nil
+}
+
+// hashSensitiveValue is the StateFunc of the attributes which are only kept
+// in the state as a hash, so that a change of their value is still detected
+func hashSensitiveValue(val interface{}) string {
+	value, _ := val.(string)
+	if value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func parseAwsTgw(ent *cdv1_api.AWSTgw, d *schema.ResourceData) error {
+	attrs := map[string]interface{}{
+		"tgw_id":                 ent.GetKey().GetTgwId().GetValue(),
+		"name":                   ent.GetName().GetValue(),
+		"state":                  ent.GetState().GetValue(),
+		"region":                 ent.GetRegion().GetValue(),
+		"account_id":             ent.GetAccountId().GetValue(),
+		"topology_name":          ent.GetTopologyName().GetValue(),
+		"attachment_ids":         ent.GetAttachmentIds().GetValues(),
+		"cnps_to_route_table_id": ent.GetCnpsToRouteTableId().GetValues(),
+		"stats":                  parseTgwStats(ent.GetStats()),
+	}
+	for attr, value := range attrs {
+		if err := d.Set(attr, value); err != nil {
+			return fmt.Errorf("Not able to set %s: %v", attr, err)
+		}
+	}
+	return nil
+}
+
+// parseTgwStats returns the stats block of a TGW or TGW attachment, empty
+// when CVaaS has no stats for it
+func parseTgwStats(stats *cdv1_api.TgwStats) []interface{} {
+	if stats == nil {
+		return nil
+	}
+	var statsTime string
+	if stats.GetStatsTime() != nil {
+		statsTime = stats.GetStatsTime().AsTime().Format(time.RFC3339)
+	}
+	return []interface{}{map[string]interface{}{
+		"bytes_in":                    stats.GetBytesIn().GetValue(),
+		"bytes_out":                   stats.GetBytesOut().GetValue(),
+		"packets_in":                  stats.GetPacketsIn().GetValue(),
+		"packets_out":                 stats.GetPacketsOut().GetValue(),
+		"packet_drop_count_blackhole": stats.GetPacketDropCountBlackhole().GetValue(),
+		"packet_drop_count_no_route":  stats.GetPacketDropCountNoRoute().GetValue(),
+		"bandwidth_in":                stats.GetBandwidthIn().GetValue(),
+		"bandwidth_out":               stats.GetBandwidthOut().GetValue(),
+		"stats_time":                  statsTime,
+	}}
+}
+
+// parseAwsTgwAttachment returns the attributes of a TGW attachment
+func parseAwsTgwAttachment(ent *cdv1_api.AWSTgwAttachment) map[string]interface{} {
+	return map[string]interface{}{
+		"attachment_id":          ent.GetKey().GetAttachmentId().GetValue(),
+		"state":                  ent.GetState().GetValue(),
+		"region":                 ent.GetRegion().GetValue(),
+		"account_id":             ent.GetAccountId().GetValue(),
+		"topology_name":          ent.GetTopologyName().GetValue(),
+		"tgw_id":                 ent.GetTgwId().GetValue(),
+		"tgw_name":               ent.GetTgwName().GetValue(),
+		"route_table_id":         ent.GetRouteTableId().GetValue(),
+		"cnps":                   ent.GetCnps().GetValue(),
+		"resource_id":            ent.GetResourceId().GetValue(),
+		"vpc_id":                 ent.GetVpcId().GetValue(),
+		"tun1_state":             ent.GetTun1State().GetValue(),
+		"tun2_state":             ent.GetTun2State().GetValue(),
+		"tun1_local_ip_outside":  ent.GetTun1LocalIpOutside().GetValue(),
+		"tun1_local_ip_inside":   ent.GetTun1LocalIpInside().GetValue(),
+		"tun1_remote_ip_outside": ent.GetTun1RemoteIpOutside().GetValue(),
+		"tun1_remote_ip_inside":  ent.GetTun1RemoteIpInside().GetValue(),
+		"tun2_local_ip_outside":  ent.GetTun2LocalIpOutside().GetValue(),
+		"tun2_local_ip_inside":   ent.GetTun2LocalIpInside().GetValue(),
+		"tun2_remote_ip_outside": ent.GetTun2RemoteIpOutside().GetValue(),
+		"tun2_remote_ip_inside":  ent.GetTun2RemoteIpInside().GetValue(),
+		"peer_attachment_ids":    ent.GetPeerAttachmentIds().GetValues(),
+		"stats":                  parseTgwStats(ent.GetStats()),
+	}
+}
+
+// parsePath returns the attributes of a DPS path
+func parsePath(ent *cdv1_api.Path) map[string]interface{} {
+	return map[string]interface{}{
+		"src_vpc_id":         ent.GetSrcVpcCloudId().GetValue(),
+		"src_vpc_name":       ent.GetSrcVpcName().GetValue(),
+		"src_region":         ent.GetSrcRegion().GetValue(),
+		"src_cloud_provider": getCloudProviderName(ent.GetSrcCpT()),
+		"local_router_id":    ent.GetLocalRtrCloudId().GetValue(),
+		"local_router_name":  ent.GetLocalRtrName().GetValue(),
+		"local_intf_ip":      ent.GetLocalIntfIpAddr().GetValue(),
+		"dst_vpc_id":         ent.GetDstVpcCloudId().GetValue(),
+		"dst_vpc_name":       ent.GetDstVpcName().GetValue(),
+		"dst_region":         ent.GetDstRegion().GetValue(),
+		"dst_cloud_provider": getCloudProviderName(ent.GetDstCpT()),
+		"remote_router_id":   ent.GetRemoteRtrCloudId().GetValue(),
+		"remote_router_name": ent.GetRemoteRtrName().GetValue(),
+		"remote_intf_ip":     ent.GetRemoteIntfIpAddr().GetValue(),
+		"underlay_type":      getUnderlayTypeName(ent.GetKey().GetUlT()),
+		"latency_ms":         int(ent.GetPathChar().GetLatencyMs().GetValue()),
+		"jitter_ms":          int(ent.GetPathChar().GetJitterMs().GetValue()),
+		"pkt_loss_pc":        int(ent.GetPathChar().GetPktLossPc().GetValue()),
+		"bw_mbps":            int(ent.GetPathChar().GetBwMbps().GetValue()),
+		"up":                 ent.GetPathChar().GetUp().GetValue(),
+		"uptime":             int(ent.GetPathChar().GetUptime().GetValue()),
+	}
+}
+
+func getUnderlayTypeName(ulType cdv1_api.UnderlayConnectionType) string {
+	switch ulType {
+	case cdv1_api.UnderlayConnectionType_UNDERLAY_CONNECTION_TYPE_IGW:
+		return "igw"
+	case cdv1_api.UnderlayConnectionType_UNDERLAY_CONNECTION_TYPE_PEERING:
+		return "peering"
+	case cdv1_api.UnderlayConnectionType_UNDERLAY_CONNECTION_TYPE_TGW:
+		return "tgw"
+	}
+	return ""
+}
+
+func getUnderlayType(name string) cdv1_api.UnderlayConnectionType {
+	switch name {
+	case "igw":
+		return cdv1_api.UnderlayConnectionType_UNDERLAY_CONNECTION_TYPE_IGW
+	case "peering":
+		return cdv1_api.UnderlayConnectionType_UNDERLAY_CONNECTION_TYPE_PEERING
+	case "tgw":
+		return cdv1_api.UnderlayConnectionType_UNDERLAY_CONNECTION_TYPE_TGW
+	}
+	return cdv1_api.UnderlayConnectionType_UNDERLAY_CONNECTION_TYPE_UNSPECIFIED
+}
+
+// legacyStateSchema returns a schema with the given attributes, used to decode
+// the state of older schema versions of a resource
+func legacyStateSchema(attrs map[string]schema.ValueType) map[string]*schema.Schema {
+	resourceSchema := map[string]*schema.Schema{}
+	for attr, valueType := range attrs {
+		resourceSchema[attr] = &schema.Schema{
+			Type:     valueType,
+			Optional: true,
+		}
+		if valueType == schema.TypeList || valueType == schema.TypeMap {
+			resourceSchema[attr].Elem = &schema.Schema{Type: schema.TypeString}
+		}
+	}
+	return resourceSchema
+}
+
+// upgradeDeprecatedAttribute removes the deprecated attribute from a raw
+// state. Its value is moved to the attribute replacing it, unless that one is
+// already set.
+func upgradeDeprecatedAttribute(rawState map[string]interface{}, deprecated,
+	attr string) map[string]interface{} {
+	value, ok := rawState[deprecated]
+	delete(rawState, deprecated)
+	if !ok || value == nil {
+		return rawState
+	}
+	switch current := rawState[attr].(type) {
+	case nil:
+	case string:
+		if current != "" {
+			return rawState
+		}
+	case []interface{}:
+		if len(current) != 0 {
+			return rawState
+		}
+	default:
+		return rawState
+	}
+	rawState[attr] = value
+	return rawState
+}
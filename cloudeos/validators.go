@@ -6,6 +6,10 @@ package cloudeos
 import (
 	"fmt"
 	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
 )
 
 func validateCIDRBlock(val interface{}, key string) (warns []string, errors []error) {
@@ -41,3 +45,36 @@ func validateIP(ipStr string) error {
 	}
 	return nil
 }
+
+func validateDuration(val interface{}, key string) (warns []string, errors []error) {
+	duration, err := time.ParseDuration(val.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%s: %q is not a valid duration. %w", key, val, err))
+		return
+	}
+	if duration <= 0 {
+		errors = append(errors, fmt.Errorf("%s: %q must be a positive duration", key, val))
+	}
+	return
+}
+
+func validateGrpcCode(val interface{}, key string) (warns []string, errors []error) {
+	if _, err := parseGrpcCode(val.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %w", key, err))
+	}
+	return
+}
+
+// parseGrpcCode returns the gRPC code with the given name, e.g. Unavailable or
+// DeadlineExceeded
+func parseGrpcCode(name string) (codes.Code, error) {
+	var supported []string
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if strings.EqualFold(c.String(), name) {
+			return c, nil
+		}
+		supported = append(supported, c.String())
+	}
+	return codes.Unknown, fmt.Errorf("%q is not a valid gRPC code. Supported codes : [%s]",
+		name, strings.Join(supported, ", "))
+}
@@ -7,14 +7,26 @@ package main
 import (
 	"github.com/aristanetworks/terraform-provider-cloudeos/cloudeos"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/plugin"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
 func main() {
+	var provider *schema.Provider
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: func() terraform.ResourceProvider {
-			return cloudeos.Provider()
+			provider = cloudeos.Provider().(*schema.Provider)
+			return provider
 		},
 	})
+
+	// Serve returns once terraform is done with the plugin, close the
+	// connection to CVaaS before exiting
+	if provider == nil {
+		return
+	}
+	if cfg, ok := provider.Meta().(cloudeos.CloudeosProvider); ok {
+		cfg.Close()
+	}
 }